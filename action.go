@@ -0,0 +1,218 @@
+package trel
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ActionCreateCard                 = "createCard"
+	ActionUpdateCard                 = "updateCard"
+	ActionDeleteCard                 = "deleteCard"
+	ActionAddMemberToCard            = "addMemberToCard"
+	ActionRemoveMemberFromCard       = "removeMemberFromCard"
+	ActionCommentCard                = "commentCard"
+	ActionAddChecklistToCard         = "addChecklistToCard"
+	ActionUpdateCheckItemStateOnCard = "updateCheckItemStateOnCard"
+	ActionCreateList                 = "createList"
+	ActionUpdateList                 = "updateList"
+)
+
+// Action is something that happened on a model, like a card being created or
+// moved. Data holds the action-type specific details; use Typed to decode them.
+type Action struct {
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Date            time.Time       `json:"date"`
+	IDMemberCreator string          `json:"idMemberCreator"`
+	MemberCreator   Member          `json:"memberCreator"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// TypedAction is an Action or one of the concrete action types, which all
+// embed Action. Switch on the concrete type to handle specific actions.
+type TypedAction interface {
+	ActionType() string
+}
+
+func (a Action) ActionType() string {
+	return a.Type
+}
+
+type CreateCardAction struct {
+	Action
+	Board Board
+	List  List
+	Card  Card
+}
+
+// UpdateCardAction is any change to a card. Old has the previous values of
+// the fields that changed; Changed reports which fields those are. When the
+// card was moved, ListBefore and ListAfter are set.
+type UpdateCardAction struct {
+	Action
+	Board      Board
+	List       List
+	Card       Card
+	Old        Card
+	ListBefore List
+	ListAfter  List
+	changed    map[string]json.RawMessage
+}
+
+// Changed reports whether field, with its JSON name like "idList", was changed.
+func (u UpdateCardAction) Changed(field string) bool {
+	_, ok := u.changed[field]
+	return ok
+}
+
+type DeleteCardAction struct {
+	Action
+	Board Board
+	List  List
+	Card  Card
+}
+
+// MemberCardAction is a member being added to or removed from a card.
+type MemberCardAction struct {
+	Action
+	Board    Board
+	Card     Card
+	IDMember string
+}
+
+type CommentCardAction struct {
+	Action
+	Board Board
+	List  List
+	Card  Card
+	Text  string
+}
+
+type AddChecklistToCardAction struct {
+	Action
+	Board     Board
+	Card      Card
+	Checklist Checklist
+}
+
+type UpdateCheckItemStateOnCardAction struct {
+	Action
+	Board     Board
+	Card      Card
+	Checklist Checklist
+	CheckItem CheckItem
+}
+
+// ListAction is a list being created or updated. For updates, Old has the
+// previous values of the fields that changed.
+type ListAction struct {
+	Action
+	Board Board
+	List  List
+	Old   List
+}
+
+// actionData has every field used in the data of the supported action types.
+type actionData struct {
+	Board      Board                      `json:"board"`
+	List       List                       `json:"list"`
+	ListBefore List                       `json:"listBefore"`
+	ListAfter  List                       `json:"listAfter"`
+	Card       Card                       `json:"card"`
+	Checklist  Checklist                  `json:"checklist"`
+	CheckItem  CheckItem                  `json:"checkItem"`
+	Old        map[string]json.RawMessage `json:"old"`
+	Text       string                     `json:"text"`
+	IDMember   string                     `json:"idMember"`
+}
+
+var typedActions = map[string]bool{
+	ActionCreateCard:                 true,
+	ActionUpdateCard:                 true,
+	ActionDeleteCard:                 true,
+	ActionAddMemberToCard:            true,
+	ActionRemoveMemberFromCard:       true,
+	ActionCommentCard:                true,
+	ActionAddChecklistToCard:         true,
+	ActionUpdateCheckItemStateOnCard: true,
+	ActionCreateList:                 true,
+	ActionUpdateList:                 true,
+}
+
+// DecodeAction decodes a JSON action, as found in webhook deliveries, into
+// its concrete type.
+func DecodeAction(data []byte) (TypedAction, error) {
+	var a Action
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+	return a.Typed()
+}
+
+// Typed decodes Data and returns the concrete type for the action. Actions
+// of other types are returned as is.
+func (a Action) Typed() (TypedAction, error) {
+	if !typedActions[a.Type] {
+		return a, nil
+	}
+
+	var d actionData
+	if len(a.Data) > 0 {
+		if err := json.Unmarshal(a.Data, &d); err != nil {
+			return nil, err
+		}
+	}
+	// Properly set the related models, like List.Cards does.
+	d.List.Board = d.Board
+	d.Card.Board = d.Board
+	d.Card.List = d.List
+	d.Checklist.Card = d.Card
+	d.Checklist.Board = d.Board
+	d.CheckItem.Checklist = d.Checklist
+
+	switch a.Type {
+	case ActionCreateCard:
+		return CreateCardAction{Action: a, Board: d.Board, List: d.List, Card: d.Card}, nil
+	case ActionUpdateCard:
+		var old Card
+		if err := remarshal(d.Old, &old); err != nil {
+			return nil, err
+		}
+		if d.ListAfter.ID != "" {
+			d.Card.List = d.ListAfter
+		}
+		return UpdateCardAction{Action: a, Board: d.Board, List: d.List, Card: d.Card, Old: old,
+			ListBefore: d.ListBefore, ListAfter: d.ListAfter, changed: d.Old}, nil
+	case ActionDeleteCard:
+		return DeleteCardAction{Action: a, Board: d.Board, List: d.List, Card: d.Card}, nil
+	case ActionAddMemberToCard, ActionRemoveMemberFromCard:
+		return MemberCardAction{Action: a, Board: d.Board, Card: d.Card, IDMember: d.IDMember}, nil
+	case ActionCommentCard:
+		return CommentCardAction{Action: a, Board: d.Board, List: d.List, Card: d.Card, Text: d.Text}, nil
+	case ActionAddChecklistToCard:
+		return AddChecklistToCardAction{Action: a, Board: d.Board, Card: d.Card, Checklist: d.Checklist}, nil
+	case ActionUpdateCheckItemStateOnCard:
+		return UpdateCheckItemStateOnCardAction{Action: a, Board: d.Board, Card: d.Card,
+			Checklist: d.Checklist, CheckItem: d.CheckItem}, nil
+	case ActionCreateList, ActionUpdateList:
+		var old List
+		if err := remarshal(d.Old, &old); err != nil {
+			return nil, err
+		}
+		return ListAction{Action: a, Board: d.Board, List: d.List, Old: old}, nil
+	}
+	return a, nil
+}
+
+// remarshal decodes the raw fields into t, which must be a pointer.
+func remarshal(raw map[string]json.RawMessage, t interface{}) error {
+	if raw == nil {
+		return nil
+	}
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, t)
+}
@@ -0,0 +1,124 @@
+package trel
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDecodeAction(t *testing.T) {
+	board := Board{ID: "b1", Name: "Board"}
+	list := List{ID: "l1", Name: "List", Board: board}
+	card := Card{ID: "c1", Name: "Card", Board: board, List: list}
+
+	cases := []struct {
+		Type   string
+		Data   string
+		Action func(a Action) TypedAction
+	}{
+		{Type: ActionCreateCard,
+			Data: `{"board": {"id": "b1", "name": "Board"}, "list": {"id": "l1", "name": "List"}, "card": {"id": "c1", "name": "Card"}}`,
+			Action: func(a Action) TypedAction {
+				return CreateCardAction{Action: a, Board: board, List: list, Card: card}
+			}},
+		{Type: ActionCommentCard,
+			Data: `{"board": {"id": "b1", "name": "Board"}, "list": {"id": "l1", "name": "List"}, "card": {"id": "c1", "name": "Card"}, "text": "Looks good"}`,
+			Action: func(a Action) TypedAction {
+				return CommentCardAction{Action: a, Board: board, List: list, Card: card, Text: "Looks good"}
+			}},
+		{Type: ActionAddMemberToCard,
+			Data: `{"board": {"id": "b1", "name": "Board"}, "card": {"id": "c1", "name": "Card"}, "idMember": "m2"}`,
+			Action: func(a Action) TypedAction {
+				return MemberCardAction{Action: a, Board: board, IDMember: "m2",
+					Card: Card{ID: "c1", Name: "Card", Board: board, List: List{Board: board}}}
+			}},
+		{Type: ActionUpdateList,
+			Data: `{"board": {"id": "b1", "name": "Board"}, "list": {"id": "l1", "name": "List"}, "old": {"name": "Old List"}}`,
+			Action: func(a Action) TypedAction {
+				return ListAction{Action: a, Board: board, List: list, Old: List{Name: "Old List"}}
+			}},
+	}
+
+	for _, c := range cases {
+		raw := []byte(fmt.Sprintf(`{"id": "a1", "type": %q, "date": "2020-01-02T03:04:05.000Z", "idMemberCreator": "m1", "data": %s}`, c.Type, c.Data))
+		compare := c.Action(Action{ID: "a1", Type: c.Type, Date: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			IDMemberCreator: "m1", Data: json.RawMessage(c.Data)})
+
+		typed, err := DecodeAction(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(compare, typed) {
+			t.Errorf("Expected %#v, got %#v\n", compare, typed)
+		}
+	}
+}
+
+func TestAction_TypedUpdateCard(t *testing.T) {
+	a := Action{Type: ActionUpdateCard, Data: json.RawMessage(`{
+		"board": {"id": "b1"},
+		"card": {"id": "c1", "name": "Card", "idList": "l2"},
+		"listBefore": {"id": "l1", "name": "To Do"},
+		"listAfter": {"id": "l2", "name": "Done"},
+		"old": {"idList": "l1"}}`)}
+
+	typed, err := a.Typed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, ok := typed.(UpdateCardAction)
+	if !ok {
+		t.Fatalf("Expected UpdateCardAction, got %#v\n", typed)
+	}
+	if !update.Changed("idList") || update.Changed("name") {
+		t.Errorf("Expected only idList to be changed, got %v\n", update.changed)
+	}
+	if update.Old.IDList != "l1" {
+		t.Errorf("Expected %q, got %q\n", "l1", update.Old.IDList)
+	}
+	if update.ListBefore.Name != "To Do" || update.ListAfter.Name != "Done" {
+		t.Errorf("Expected move from To Do to Done, got %q to %q\n", update.ListBefore.Name, update.ListAfter.Name)
+	}
+	if update.Card.List.ID != "l2" || update.Card.Board.ID != "b1" {
+		t.Errorf("Expected card on list l2 and board b1, got %q and %q\n", update.Card.List.ID, update.Card.Board.ID)
+	}
+}
+
+func TestAction_TypedCheckItemState(t *testing.T) {
+	a := Action{Type: ActionUpdateCheckItemStateOnCard, Data: json.RawMessage(`{
+		"card": {"id": "c1"},
+		"checklist": {"id": "cl1", "name": "Checklist"},
+		"checkItem": {"id": "ci1", "name": "Item", "state": "complete"}}`)}
+
+	typed, err := a.Typed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	update, ok := typed.(UpdateCheckItemStateOnCardAction)
+	if !ok {
+		t.Fatalf("Expected UpdateCheckItemStateOnCardAction, got %#v\n", typed)
+	}
+	if update.CheckItem.State != StateComplete || update.CheckItem.Checklist.ID != "cl1" {
+		t.Errorf("Expected complete item on checklist cl1, got %v\n", update.CheckItem)
+	}
+	if update.Checklist.Card.ID != "c1" {
+		t.Errorf("Expected checklist on card c1, got %q\n", update.Checklist.Card.ID)
+	}
+}
+
+func TestAction_TypedUnknown(t *testing.T) {
+	a := Action{ID: "1", Type: "updateBoard", Data: json.RawMessage(`{"board": "not an object"}`)}
+
+	typed, err := a.Typed()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, typed) {
+		t.Errorf("Expected %#v, got %#v\n", a, typed)
+	}
+}
@@ -0,0 +1,179 @@
+package trel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Trello answers at most this many URLs in one batch request.
+const maxBatchURLs = 10
+
+// BatchResult is the response to one of the URLs of a batch. Err is set if
+// Trello couldn't answer it, usually with an HTTPRequestError.
+type BatchResult struct {
+	URL        string
+	StatusCode int
+	Body       json.RawMessage
+	Err        error
+}
+
+// Decode decodes the body into t, which must be a pointer, or returns Err.
+func (r BatchResult) Decode(t interface{}) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	return json.Unmarshal(r.Body, t)
+}
+
+// BatchError has the errors of the items of a batch that failed, by their
+// index.
+type BatchError map[int]error
+
+func (b BatchError) Error() string {
+	first := -1
+	for i := range b {
+		if first == -1 || i < first {
+			first = i
+		}
+	}
+	return fmt.Sprintf("%d batch items failed, the first one (%d) with: %v", len(b), first, b[first])
+}
+
+// Batch gets the API paths in urls, like "cards/{id}/checklists", with as few
+// requests as possible. The results are in the same order as urls, and each
+// one has its own error; the returned error is only set if a whole request
+// failed. urls can't contain commas, as Trello uses them to separate the URLs;
+// no requests are sent if one does.
+func (c *Client) Batch(urls []string) ([]BatchResult, error) {
+	return c.BatchContext(context.Background(), urls)
+}
+
+func (c *Client) BatchContext(ctx context.Context, urls []string) ([]BatchResult, error) {
+	for _, u := range urls {
+		if strings.Contains(u, ",") {
+			return nil, fmt.Errorf("batch URL %q contains a comma, which Trello uses to separate URLs", c.redact(u))
+		}
+	}
+
+	out := make([]BatchResult, 0, len(urls))
+	for start := 0; start < len(urls); start += maxBatchURLs {
+		end := start + maxBatchURLs
+		if end > len(urls) {
+			end = len(urls)
+		}
+		results, err := c.batch(ctx, urls[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, results...)
+	}
+	return out, nil
+}
+
+func (c *Client) batch(ctx context.Context, urls []string) ([]BatchResult, error) {
+	paths := make([]string, len(urls))
+	for i, u := range urls {
+		paths[i] = "/" + strings.TrimPrefix(u, "/")
+	}
+	apiurl := "batch?" + url.Values{"urls": {strings.Join(paths, ",")}}.Encode()
+	var items []map[string]json.RawMessage
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &items); err != nil {
+		return nil, err
+	}
+	if len(items) != len(urls) {
+		return nil, fmt.Errorf("expected %d batch results, got %d", len(urls), len(items))
+	}
+
+	out := make([]BatchResult, len(urls))
+	for i, item := range items {
+		out[i] = c.batchResult(urls[i], item)
+	}
+	return out, nil
+}
+
+// batchResult decodes an item of a batch response. Trello sends the body of
+// each response keyed by its status code, like {"200": {...}}, or an error
+// object with a statusCode.
+func (c *Client) batchResult(u string, item map[string]json.RawMessage) BatchResult {
+	out := BatchResult{URL: u}
+	if len(item) == 1 {
+		for key, body := range item {
+			if status, err := strconv.Atoi(key); err == nil {
+				out.StatusCode, out.Body = status, body
+			}
+		}
+	}
+	if out.StatusCode == 0 {
+		var status int
+		json.Unmarshal(item["statusCode"], &status)
+		out.StatusCode = status
+	}
+	if out.StatusCode >= 200 && out.StatusCode < 300 {
+		return out
+	}
+
+	err := HTTPRequestError{StatusCode: out.StatusCode, Method: http.MethodGet, Path: c.redact(u)}
+	if out.Body != nil {
+		err.Body = string(out.Body)
+		var message string
+		if json.Unmarshal(out.Body, &message) == nil {
+			err.Message = message
+		}
+	} else {
+		b, _ := json.Marshal(item)
+		err.Body = string(b)
+		json.Unmarshal(item["message"], &err.Message)
+	}
+	out.Err = err
+	return out
+}
+
+// Checklists gets the checklists of every card, with a batch of requests. The
+// result has the checklists of each card at the same index. If some cards
+// failed, the error is a BatchError, and the others are still set.
+func (cs Cards) Checklists() ([]Checklists, error) {
+	return cs.ChecklistsContext(context.Background())
+}
+
+func (cs Cards) ChecklistsContext(ctx context.Context) ([]Checklists, error) {
+	if len(cs) == 0 {
+		return nil, nil
+	}
+
+	c := cs[0].client
+	urls := make([]string, len(cs))
+	for i := range cs {
+		urls[i] = apiPath("cards", cs[i].ID, "checklists")
+	}
+	results, err := c.BatchContext(ctx, urls)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Checklists, len(cs))
+	errs := BatchError{}
+	for i := range results {
+		if err := results[i].Decode(&out[i]); err != nil {
+			errs[i] = err
+			continue
+		}
+		for j := range out[i] {
+			out[i][j].Card = cs[i]
+			out[i][j].Board = cs[i].Board
+			out[i][j].client = c
+			for k := range out[i][j].CheckItems {
+				out[i][j].CheckItems[k].Checklist = out[i][j]
+				out[i][j].CheckItems[k].client = c
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return out, errs
+	}
+	return out, nil
+}
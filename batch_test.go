@@ -0,0 +1,142 @@
+package trel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// batchHandler answers batch requests for card checklists. Cards with an ID
+// starting with "missing" aren't found.
+func batchHandler(requests *[]int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		urls := strings.Split(r.URL.Query().Get("urls"), ",")
+		*requests = append(*requests, len(urls))
+
+		var items []interface{}
+		for _, u := range urls {
+			id := strings.Split(strings.TrimPrefix(u, "/cards/"), "/")[0]
+			switch {
+			case strings.HasPrefix(id, "missing"):
+				items = append(items, map[string]interface{}{"name": "NotFoundError", "message": "model not found", "statusCode": 404})
+			case strings.HasPrefix(id, "gone"):
+				items = append(items, map[string]interface{}{"410": "card deleted"})
+			default:
+				items = append(items, map[string]interface{}{"200": []interface{}{
+					map[string]interface{}{"id": "cl-" + id, "idCard": id, "checkItems": []interface{}{map[string]string{"id": "ci-" + id}}},
+				}})
+			}
+		}
+		json.NewEncoder(w).Encode(items)
+	}
+}
+
+func TestClient_Batch(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var requests []int
+	mux.HandleFunc("/batch", batchHandler(&requests))
+
+	var urls []string
+	for i := 0; i < 25; i++ {
+		urls = append(urls, fmt.Sprintf("cards/c%d/checklists", i))
+	}
+	urls[12] = "/cards/missing/checklists"
+	urls[20] = "cards/gone/checklists"
+
+	results, err := client.Batch(urls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "[10 10 5]"; want != fmt.Sprint(requests) {
+		t.Errorf("Expected requests of %s URLs, got %v\n", want, requests)
+	}
+	if len(results) != len(urls) {
+		t.Fatalf("Expected %d results, got %d\n", len(urls), len(results))
+	}
+	for i, result := range results {
+		if urls[i] != result.URL {
+			t.Errorf("Expected %q, got %q\n", urls[i], result.URL)
+		}
+		var checklists Checklists
+		err := result.Decode(&checklists)
+		switch i {
+		case 12:
+			if !IsNotFound(err) || !strings.Contains(err.Error(), "model not found") {
+				t.Errorf("Expected a not found error, got %v\n", err)
+			}
+		case 20:
+			if result.StatusCode != http.StatusGone || err == nil || !strings.Contains(err.Error(), "card deleted") {
+				t.Errorf("Expected a gone error, got %d and %v\n", result.StatusCode, err)
+			}
+		default:
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := fmt.Sprintf("cl-c%d", i); len(checklists) != 1 || checklists[0].ID != want {
+				t.Errorf("Expected checklist %q, got %#v\n", want, checklists)
+			}
+		}
+	}
+}
+
+func TestClient_BatchComma(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var requests []int
+	mux.HandleFunc("/batch", batchHandler(&requests))
+
+	urls := []string{"cards/c1/checklists", "search?query=a,b", "cards/c2/checklists"}
+	_, err := client.Batch(urls)
+	if err == nil || !strings.Contains(err.Error(), `"search?query=a,b"`) {
+		t.Errorf("Expected an error naming the URL with a comma, got %v\n", err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("Expected no requests, got %d\n", len(requests))
+	}
+}
+
+func TestCards_Checklists(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var requests []int
+	mux.HandleFunc("/batch", batchHandler(&requests))
+
+	board := Board{ID: "b", client: client}
+	cards := Cards{
+		{ID: "c1", Board: board, client: client},
+		{ID: "missing", Board: board, client: client},
+		{ID: "c3", Board: board, client: client},
+	}
+
+	checklists, err := cards.Checklists()
+	batchErr, ok := err.(BatchError)
+	if !ok || len(batchErr) != 1 || !IsNotFound(batchErr[1]) {
+		t.Fatalf("Expected a not found error for item 1, got %v\n", err)
+	}
+
+	if len(requests) != 1 {
+		t.Errorf("Expected 1 request, got %d\n", len(requests))
+	}
+	for _, i := range []int{0, 2} {
+		if len(checklists[i]) != 1 {
+			t.Fatalf("Expected 1 checklist, got %#v\n", checklists[i])
+		}
+		cl := checklists[i][0]
+		if cl.ID != "cl-"+cards[i].ID || cl.Card.ID != cards[i].ID || cl.Board.ID != "b" || cl.client != client {
+			t.Errorf("Expected the checklist of card %s, got %#v\n", cards[i].ID, cl)
+		}
+		if cl.CheckItems[0].Checklist.ID != cl.ID || cl.CheckItems[0].client != client {
+			t.Errorf("Expected the items to be wired to the checklist, got %#v\n", cl.CheckItems)
+		}
+	}
+	if checklists[1] != nil {
+		t.Errorf("Expected no checklists for the missing card, got %#v\n", checklists[1])
+	}
+}
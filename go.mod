@@ -1 +1,3 @@
 module github.com/ifo/trel
+
+go 1.13
@@ -0,0 +1,105 @@
+package trel
+
+import (
+	"context"
+	"net/http"
+)
+
+// Organization is a Trello Workspace, which boards and members belong to.
+type Organization struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"` // The short name used in URLs.
+	DisplayName string `json:"displayName"`
+	Description string `json:"desc"`
+	URL         string `json:"url"`
+	client      *Client
+}
+
+type Organizations []Organization
+
+// Organizations gets the organizations a member, by id or username, belongs
+// to. Use "me" for the token's member.
+func (c *Client) Organizations(member string) (Organizations, error) {
+	return c.OrganizationsContext(context.Background(), member)
+}
+
+func (c *Client) OrganizationsContext(ctx context.Context, member string) (Organizations, error) {
+	apiurl := apiPath("members", member, "organizations")
+	var out Organizations
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].client = c
+	}
+	return out, nil
+}
+
+// Organization gets an organization by id or name.
+func (c *Client) Organization(idOrName string) (Organization, error) {
+	return c.OrganizationContext(context.Background(), idOrName)
+}
+
+func (c *Client) OrganizationContext(ctx context.Context, idOrName string) (Organization, error) {
+	apiurl := apiPath("organizations", idOrName)
+	var out Organization
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return Organization{}, err
+	}
+	out.client = c
+	return out, nil
+}
+
+func (o Organization) Boards() (Boards, error) {
+	return o.BoardsContext(context.Background())
+}
+
+func (o Organization) BoardsContext(ctx context.Context) (Boards, error) {
+	c := o.client
+	apiurl := apiPath("organizations", o.ID, "boards")
+	var out Boards
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].client = c
+	}
+	return out, nil
+}
+
+func (o Organization) Members() (Members, error) {
+	return o.MembersContext(context.Background())
+}
+
+func (o Organization) MembersContext(ctx context.Context) (Members, error) {
+	c := o.client
+	apiurl := apiPath("organizations", o.ID, "members")
+	var out Members
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].client = c
+	}
+	return out, nil
+}
+
+// NewBoard creates a board in the organization. options.IDOrganization is
+// ignored.
+func (o Organization) NewBoard(name string, options BoardOptions) (Board, error) {
+	return o.NewBoardContext(context.Background(), name, options)
+}
+
+func (o Organization) NewBoardContext(ctx context.Context, name string, options BoardOptions) (Board, error) {
+	options.IDOrganization = o.ID
+	return o.client.NewBoardContext(ctx, name, options)
+}
+
+func (orgs Organizations) Find(name string) (*Organization, error) {
+	for i := range orgs {
+		if orgs[i].Name == name || orgs[i].DisplayName == name {
+			return &orgs[i], nil
+		}
+	}
+	return &Organization{}, NotFoundError{Type: "Organization", Identifier: name}
+}
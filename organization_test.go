@@ -0,0 +1,143 @@
+package trel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_Organizations(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/members/me/organizations", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "o1", "name": "acme", "displayName": "Acme", "desc": "Everything", "url": "https://trello.com/acme"}]`)
+	})
+
+	compare := Organizations{{ID: "o1", Name: "acme", DisplayName: "Acme", Description: "Everything",
+		URL: "https://trello.com/acme", client: client}}
+
+	orgs, err := client.Organizations("me")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, orgs) {
+		t.Errorf("Expected %#v, got %#v\n", compare, orgs)
+	}
+}
+
+func TestClient_Organization(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/organizations/acme", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "o1", "name": "acme", "displayName": "Acme"}`)
+	})
+
+	compare := Organization{ID: "o1", Name: "acme", DisplayName: "Acme", client: client}
+
+	org, err := client.Organization("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, org) {
+		t.Errorf("Expected %#v, got %#v\n", compare, org)
+	}
+}
+
+func TestOrganization_Boards(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/organizations/o1/boards", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "b1", "name": "Project", "idOrganization": "o1"}]`)
+	})
+
+	compare := Boards{{ID: "b1", Name: "Project", IDOrganization: "o1", client: client}}
+
+	org := Organization{ID: "o1", client: client}
+	boards, err := org.Boards()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, boards) {
+		t.Errorf("Expected %#v, got %#v\n", compare, boards)
+	}
+}
+
+func TestOrganization_Members(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/organizations/o1/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "m1", "username": "alice", "fullName": "Alice"}]`)
+	})
+
+	compare := Members{{ID: "m1", Username: "alice", FullName: "Alice", client: client}}
+
+	org := Organization{ID: "o1", client: client}
+	members, err := org.Members()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, members) {
+		t.Errorf("Expected %#v, got %#v\n", compare, members)
+	}
+}
+
+func TestOrganization_NewBoard(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/boards", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "b1", "name": "Project", "idOrganization": "o1"}`)
+	})
+
+	org := Organization{ID: "o1", client: client}
+	board, err := org.NewBoard("Project", BoardOptions{IDOrganization: "other", PermissionLevel: "org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareBody := map[string]interface{}{"name": "Project", "defaultLists": false, "idOrganization": "o1",
+		"prefs_permissionLevel": "org"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	compare := Board{ID: "b1", Name: "Project", IDOrganization: "o1", client: client}
+	if !reflect.DeepEqual(compare, board) {
+		t.Errorf("Expected %#v, got %#v\n", compare, board)
+	}
+}
+
+func TestOrganizations_Find(t *testing.T) {
+	orgs := Organizations{{ID: "o1", Name: "acme", DisplayName: "Acme"}, {ID: "o2", Name: "globex", DisplayName: "Globex"}}
+
+	cases := []struct {
+		Name string
+		ID   string
+		Err  error
+	}{
+		{Name: "acme", ID: "o1", Err: nil},
+		{Name: "Globex", ID: "o2", Err: nil},
+		{Name: "initech", ID: "", Err: NotFoundError{Type: "Organization", Identifier: "initech"}},
+	}
+
+	for _, c := range cases {
+		org, err := orgs.Find(c.Name)
+		if c.Err != err {
+			t.Errorf("Expected %v, got %v\n", c.Err, err)
+		}
+		if c.ID != org.ID {
+			t.Errorf("Expected %q, got %q\n", c.ID, org.ID)
+		}
+	}
+}
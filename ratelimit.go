@@ -0,0 +1,174 @@
+package trel
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimits are request budgets over a rolling window, per API key and per
+// token, as enforced by Trello.
+type RateLimits struct {
+	KeyRequests   int
+	TokenRequests int
+	Window        time.Duration
+}
+
+var DefaultRateLimits = RateLimits{
+	KeyRequests:   300,
+	TokenRequests: 100,
+	Window:        10 * time.Second,
+}
+
+// RateLimiter delays requests so they stay within the key and token budgets.
+// New sets one as the Client's Limiter; set another for other limits.
+// Limiters with the same API key and limits share the key budget, and
+// limiters with the same token and limits share the token budget, so any
+// number of Clients can be limited together.
+type RateLimiter struct {
+	key   bucketKey
+	token bucketKey
+}
+
+// bucketKey names a shared budget. A budget with no requests or no window
+// is unlimited.
+type bucketKey struct {
+	name     string
+	requests int
+	window   time.Duration
+}
+
+// Budgets that have refilled completely are the same as new ones, so they
+// are dropped every sweepInterval to keep buckets from growing with every
+// key and token that was ever used.
+const sweepInterval = time.Minute
+
+var (
+	bucketsMu sync.Mutex
+	buckets   = map[bucketKey]*bucket{}
+	lastSweep time.Time
+)
+
+// NewRateLimiter returns a limiter for apiKey and token with the given limits.
+func NewRateLimiter(apiKey, token string, limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		key:   bucketKey{"key:" + apiKey, limits.KeyRequests, limits.Window},
+		token: bucketKey{"token:" + token, limits.TokenRequests, limits.Window},
+	}
+}
+
+// Wait blocks until a request is allowed by both budgets, or ctx is done.
+func (r *RateLimiter) Wait(ctx context.Context) error {
+	now := time.Now()
+	bucketsMu.Lock()
+	sweepBuckets(now)
+	key, token := sharedBucket(r.key), sharedBucket(r.token)
+	keyWait := key.reserve(now)
+	tokenWait := token.reserve(now)
+	bucketsMu.Unlock()
+
+	wait := keyWait
+	if tokenWait > wait {
+		wait = tokenWait
+	}
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleepContext(ctx, wait); err != nil {
+		// The request won't be sent, so give back what it reserved.
+		key.cancel()
+		token.cancel()
+		return err
+	}
+	return nil
+}
+
+// sharedBucket returns the bucket for k, creating it if needed. bucketsMu
+// must be held.
+func sharedBucket(k bucketKey) *bucket {
+	if k.requests <= 0 || k.window <= 0 {
+		return nil
+	}
+	if b, ok := buckets[k]; ok {
+		return b
+	}
+	b := newBucket(k.requests, k.window)
+	buckets[k] = b
+	return b
+}
+
+// sweepBuckets drops the buckets that are full at now, at most once every
+// sweepInterval. bucketsMu must be held.
+func sweepBuckets(now time.Time) {
+	if now.Sub(lastSweep) < sweepInterval {
+		return
+	}
+	lastSweep = now
+	for k, b := range buckets {
+		if b.full(now) {
+			delete(buckets, k)
+		}
+	}
+}
+
+// bucket is a token bucket holding up to capacity tokens and refilling at
+// capacity tokens per window. A nil bucket allows everything.
+type bucket struct {
+	mu       sync.Mutex
+	capacity float64
+	rate     float64 // Tokens per second.
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(requests int, window time.Duration) *bucket {
+	return &bucket{
+		capacity: float64(requests),
+		rate:     float64(requests) / window.Seconds(),
+		tokens:   float64(requests),
+		last:     time.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before it may be used.
+// Tokens can go negative, which queues waiters in the order they reserved.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if now.After(b.last) {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.capacity {
+			b.tokens = b.capacity
+		}
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// full reports whether the bucket will have refilled completely by now.
+func (b *bucket) full(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.capacity
+}
+
+func (b *bucket) cancel() {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.capacity {
+		b.tokens = b.capacity
+	}
+}
@@ -0,0 +1,187 @@
+package trel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBucket_reserve(t *testing.T) {
+	b := newBucket(2, time.Second)
+	now := b.last
+
+	cases := []struct {
+		Now  time.Time
+		Wait time.Duration
+	}{
+		{Now: now, Wait: 0},
+		{Now: now, Wait: 0},
+		{Now: now, Wait: 500 * time.Millisecond},
+		{Now: now, Wait: time.Second},
+		{Now: now.Add(time.Second), Wait: 500 * time.Millisecond},
+		{Now: now.Add(5 * time.Second), Wait: 0},
+		{Now: now.Add(5 * time.Second), Wait: 0},
+		{Now: now.Add(5 * time.Second), Wait: 500 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		wait := b.reserve(c.Now)
+		if wait != c.Wait {
+			t.Errorf("Expected %s, got %s\n", c.Wait, wait)
+		}
+	}
+}
+
+func resetBuckets() {
+	bucketsMu.Lock()
+	buckets = map[bucketKey]*bucket{}
+	lastSweep = time.Time{}
+	bucketsMu.Unlock()
+}
+
+func lookupBucket(k bucketKey) *bucket {
+	bucketsMu.Lock()
+	defer bucketsMu.Unlock()
+	return sharedBucket(k)
+}
+
+func TestNewRateLimiter(t *testing.T) {
+	resetBuckets()
+	limits := RateLimits{KeyRequests: 3, TokenRequests: 2, Window: time.Minute}
+	l1 := NewRateLimiter("ratelimit-key", "ratelimit-token-1", limits)
+	l2 := NewRateLimiter("ratelimit-key", "ratelimit-token-1", limits)
+	l3 := NewRateLimiter("ratelimit-key", "ratelimit-token-2", limits)
+
+	if lookupBucket(l1.key) != lookupBucket(l2.key) || lookupBucket(l1.key) != lookupBucket(l3.key) {
+		t.Errorf("Expected limiters with the same key to share a bucket\n")
+	}
+	if lookupBucket(l1.token) != lookupBucket(l2.token) {
+		t.Errorf("Expected limiters with the same token to share a bucket\n")
+	}
+	if lookupBucket(l1.token) == lookupBucket(l3.token) {
+		t.Errorf("Expected limiters with different tokens not to share a bucket\n")
+	}
+
+	other := NewRateLimiter("ratelimit-key", "ratelimit-token-1", RateLimits{KeyRequests: 1, TokenRequests: 1, Window: time.Minute})
+	if lookupBucket(other.key) == lookupBucket(l1.key) || lookupBucket(other.token) == lookupBucket(l1.token) {
+		t.Errorf("Expected limiters with different limits not to share a bucket\n")
+	}
+
+	unlimited := NewRateLimiter("ratelimit-key-unlimited", "ratelimit-token-unlimited", RateLimits{})
+	if lookupBucket(unlimited.key) != nil || lookupBucket(unlimited.token) != nil {
+		t.Errorf("Expected zero limits to be unlimited\n")
+	}
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	resetBuckets()
+	limits := RateLimits{KeyRequests: 10, TokenRequests: 1, Window: time.Hour}
+	l1 := NewRateLimiter("wait-key", "wait-token", limits)
+	l2 := NewRateLimiter("wait-key", "wait-token", limits)
+
+	if err := l1.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// The token budget is used up by l1, so l2 has to wait for it.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l2.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %q, got %q\n", context.DeadlineExceeded, err)
+	}
+
+	// Cancelled waits give back their reservation.
+	key, token := lookupBucket(l2.key), lookupBucket(l2.token)
+	if token.tokens < -0.1 || key.tokens < 8.9 {
+		t.Errorf("Expected cancelled reservation to be returned, got %v and %v\n", token.tokens, key.tokens)
+	}
+}
+
+func TestNew_Limiter(t *testing.T) {
+	resetBuckets()
+	client := New(nil, "new-key", "new-token")
+
+	if client.Limiter == nil {
+		t.Fatal("Expected New to set a limiter")
+	}
+	compare := NewRateLimiter("new-key", "new-token", DefaultRateLimits)
+	if *client.Limiter != *compare {
+		t.Errorf("Expected the limiter to use the key and token budgets\n")
+	}
+	if key := lookupBucket(client.Limiter.key); key.capacity != float64(DefaultRateLimits.KeyRequests) {
+		t.Errorf("Expected the default limits, got a key budget of %v\n", key.capacity)
+	}
+
+	// A limiter with other limits for the same key and token isn't held to
+	// the default budgets.
+	client.Limiter = NewRateLimiter("new-key", "new-token", RateLimits{KeyRequests: 10, TokenRequests: 1, Window: time.Hour})
+	if err := client.Limiter.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.Limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the custom token budget to be used up, got %v\n", err)
+	}
+}
+
+func TestSweepBuckets(t *testing.T) {
+	resetBuckets()
+	limits := RateLimits{KeyRequests: 2, TokenRequests: 2, Window: time.Second}
+	used := NewRateLimiter("sweep-key", "sweep-token-1", limits)
+	idle := NewRateLimiter("sweep-key", "sweep-token-2", limits)
+	if err := used.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	lookupBucket(idle.token)
+
+	bucketsMu.Lock()
+	now := time.Now()
+	lastSweep = time.Time{}
+	sweepBuckets(now)
+	_, keyKept := buckets[used.key]
+	_, usedKept := buckets[used.token]
+	_, idleKept := buckets[idle.token]
+	bucketsMu.Unlock()
+	if !keyKept || !usedKept || idleKept {
+		t.Errorf("Expected only the full bucket to be dropped, got key %t, used %t, idle %t\n", keyKept, usedKept, idleKept)
+	}
+
+	bucketsMu.Lock()
+	sweepBuckets(now.Add(sweepInterval))
+	n := len(buckets)
+	bucketsMu.Unlock()
+	if n != 0 {
+		t.Errorf("Expected refilled buckets to be dropped, got %d\n", n)
+	}
+}
+
+func TestClient_Limiter(t *testing.T) {
+	resetBuckets()
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Limiter = NewRateLimiter("client-key", "client-token", RateLimits{KeyRequests: 2, TokenRequests: 2, Window: 100 * time.Millisecond})
+
+	requests := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"name": "Test", "id": "1234"}`)
+	})
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := client.Board("1234"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected requests to be limited, took %s\n", elapsed)
+	}
+	if requests != 4 {
+		t.Errorf("Expected 4 requests, got %d\n", requests)
+	}
+}
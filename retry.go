@@ -0,0 +1,114 @@
+package trel
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Requests are retried
+// after network errors, rate limiting (429) and server errors (5xx).
+// Rate limited requests weren't run by Trello, so they're retried whatever
+// their method. The zero value disables retries.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseDelay is the wait before the first retry. It doubles with every
+	// attempt after that, up to MaxDelay, and is randomized by up to half.
+	BaseDelay time.Duration
+	// MaxDelay also caps the wait asked for by a Retry-After header; if the
+	// header asks for longer, the request isn't retried.
+	MaxDelay time.Duration
+	// RetryNonIdempotent allows retrying methods like POST, which may have
+	// taken effect even though the request failed.
+	RetryNonIdempotent bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// backoff reports whether the failed attempt should be retried, and how long
+// to wait before doing so. resp is nil if the request failed without a response.
+func (p RetryPolicy) backoff(method string, attempt int, resp *http.Response, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	if resp != nil && !retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	rateLimited := resp != nil && resp.StatusCode == http.StatusTooManyRequests
+	if !rateLimited && !p.RetryNonIdempotent && !isIdempotent(method) {
+		return 0, false
+	}
+
+	if resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxDelay > 0 && wait > p.MaxDelay {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+
+	wait := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || wait < p.MaxDelay); i++ {
+		wait *= 2
+	}
+	if p.MaxDelay > 0 && wait > p.MaxDelay {
+		wait = p.MaxDelay
+	}
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half+1))
+	}
+	return wait, true
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// parseRetryAfter handles both forms of the Retry-After header: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,216 @@
+package trel
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNew_Retry(t *testing.T) {
+	client := New(nil, "", "")
+	if !reflect.DeepEqual(DefaultRetryPolicy, client.Retry) {
+		t.Errorf("Expected %#v, got %#v\n", DefaultRetryPolicy, client.Retry)
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	cases := []struct {
+		Statuses   []int
+		Attempts   int
+		StatusCode int
+	}{
+		{Statuses: []int{502, 200}, Attempts: 2, StatusCode: 0},
+		{Statuses: []int{500, 503, 200}, Attempts: 3, StatusCode: 0},
+		{Statuses: []int{429, 429, 429}, Attempts: 3, StatusCode: 429},
+		{Statuses: []int{404}, Attempts: 1, StatusCode: 404},
+	}
+
+	var statuses []int
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[attempts]
+		attempts++
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"name": "Test", "id": "1234"}`)
+	})
+
+	for _, c := range cases {
+		statuses, attempts = c.Statuses, 0
+
+		_, err := client.Board("1234")
+		if status := statusCode(err); c.StatusCode != status {
+			t.Errorf("Expected status %d, got %d (%v)\n", c.StatusCode, status, err)
+		}
+		if c.Attempts != attempts {
+			t.Errorf("Expected %d attempts, got %d\n", c.Attempts, attempts)
+		}
+	}
+}
+
+func statusCode(err error) int {
+	var httpErr HTTPRequestError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+	return 0
+}
+
+func TestClient_RetryNonIdempotent(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	_, err := client.NewWebhook("Card", "example.com", "1234")
+	if status := statusCode(err); status != http.StatusBadGateway {
+		t.Errorf("Expected status %d, got %d (%v)\n", http.StatusBadGateway, status, err)
+	}
+	if attempts != 1 {
+		t.Errorf("Expected 1 attempt, got %d\n", attempts)
+	}
+
+	client.Retry.RetryNonIdempotent = true
+	attempts = 0
+	client.NewWebhook("Card", "example.com", "1234")
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d\n", attempts)
+	}
+}
+
+func TestClient_RetryRateLimitedPost(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"id": "1"}`)
+	})
+
+	if _, err := client.NewWebhook("Card", "example.com", "1234"); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Errorf("Expected 3 attempts, got %d\n", attempts)
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour}
+
+	attempts := 0
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"name": "Test", "id": "1234"}`)
+	})
+
+	board, err := client.Board("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compare := Board{ID: "1234", Name: "Test", client: client}
+	if !reflect.DeepEqual(compare, board) {
+		t.Errorf("Expected %#v, got %#v\n", compare, board)
+	}
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	cases := []struct {
+		Attempt int
+		Min     time.Duration
+		Max     time.Duration
+	}{
+		{Attempt: 1, Min: 50 * time.Millisecond, Max: 100 * time.Millisecond},
+		{Attempt: 2, Min: 100 * time.Millisecond, Max: 200 * time.Millisecond},
+		{Attempt: 3, Min: 150 * time.Millisecond, Max: 300 * time.Millisecond},
+		{Attempt: 4, Min: 150 * time.Millisecond, Max: 300 * time.Millisecond},
+	}
+
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	for _, c := range cases {
+		wait, ok := policy.backoff(http.MethodGet, c.Attempt, resp, nil)
+		if !ok {
+			t.Fatalf("Expected attempt %d to be retried\n", c.Attempt)
+		}
+		if wait < c.Min || wait > c.Max {
+			t.Errorf("Expected wait between %s and %s, got %s\n", c.Min, c.Max, wait)
+		}
+	}
+
+	if _, ok := policy.backoff(http.MethodGet, 5, resp, nil); ok {
+		t.Errorf("Expected no retry after MaxAttempts\n")
+	}
+}
+
+func TestRetryPolicy_backoffRetryAfter(t *testing.T) {
+	cases := []struct {
+		MaxDelay   time.Duration
+		RetryAfter string
+		Wait       time.Duration
+		OK         bool
+	}{
+		{MaxDelay: time.Minute, RetryAfter: "30", Wait: 30 * time.Second, OK: true},
+		{MaxDelay: time.Minute, RetryAfter: "60", Wait: time.Minute, OK: true},
+		{MaxDelay: time.Minute, RetryAfter: "3600", Wait: 0, OK: false},
+		{MaxDelay: 0, RetryAfter: "3600", Wait: time.Hour, OK: true},
+	}
+
+	for _, c := range cases {
+		policy := RetryPolicy{MaxAttempts: 2, MaxDelay: c.MaxDelay}
+		resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {c.RetryAfter}}}
+		wait, ok := policy.backoff(http.MethodPost, 1, resp, nil)
+		if wait != c.Wait || ok != c.OK {
+			t.Errorf("Expected %s, %t, got %s, %t\n", c.Wait, c.OK, wait, ok)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		Header string
+		Wait   time.Duration
+		OK     bool
+	}{
+		{Header: "", Wait: 0, OK: false},
+		{Header: "3", Wait: 3 * time.Second, OK: true},
+		{Header: "-1", Wait: 0, OK: false},
+		{Header: "Wed, 01 Jan 2020 00:00:10 GMT", Wait: 10 * time.Second, OK: true},
+		{Header: "Tue, 31 Dec 2019 23:59:00 GMT", Wait: 0, OK: true},
+		{Header: "soon", Wait: 0, OK: false},
+	}
+
+	for _, c := range cases {
+		wait, ok := parseRetryAfter(c.Header, now)
+		if wait != c.Wait || ok != c.OK {
+			t.Errorf("Expected %s, %t, got %s, %t\n", c.Wait, c.OK, wait, ok)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package trel
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The model types that can be searched for.
+const (
+	SearchBoards        = "boards"
+	SearchCards         = "cards"
+	SearchMembers       = "members"
+	SearchOrganizations = "organizations"
+)
+
+var searchModelTypes = []string{SearchBoards, SearchCards, SearchMembers, SearchOrganizations}
+
+type SearchOptions struct {
+	// ModelTypes are the types of models to search for, all of them if it's
+	// empty.
+	ModelTypes []string
+	// IDBoards limits the search to these boards.
+	IDBoards []string
+	// IDOrganizations limits the search to the boards of these organizations.
+	IDOrganizations []string
+	// Partial matches words by their beginning, so "dev" finds "development".
+	Partial bool
+	// Limit is the maximum number of results of each type, Trello's default
+	// of 10 if it isn't set.
+	Limit int
+}
+
+// SearchResults has the results of a search, by type.
+type SearchResults struct {
+	Boards        Boards        `json:"boards"`
+	Cards         Cards         `json:"cards"`
+	Members       Members       `json:"members"`
+	Organizations Organizations `json:"organizations"`
+}
+
+// Search searches for query, which can use Trello's search operators like
+// "board:" or "is:open".
+func (c *Client) Search(query string, options SearchOptions) (SearchResults, error) {
+	return c.SearchContext(context.Background(), query, options)
+}
+
+func (c *Client) SearchContext(ctx context.Context, query string, options SearchOptions) (SearchResults, error) {
+	modelTypes := options.ModelTypes
+	if len(modelTypes) == 0 {
+		modelTypes = searchModelTypes
+	}
+	params := url.Values{
+		"query":      {query},
+		"modelTypes": {strings.Join(modelTypes, ",")},
+		"partial":    {strconv.FormatBool(options.Partial)},
+		// Include the board and list of every card, to wire them up.
+		"card_board": {"true"},
+		"card_list":  {"true"},
+	}
+	if len(options.IDBoards) > 0 {
+		params.Set("idBoards", strings.Join(options.IDBoards, ","))
+	}
+	if len(options.IDOrganizations) > 0 {
+		params.Set("idOrganizations", strings.Join(options.IDOrganizations, ","))
+	}
+	if options.Limit > 0 {
+		for _, modelType := range modelTypes {
+			params.Set(modelType+"_limit", strconv.Itoa(options.Limit))
+		}
+	}
+
+	apiurl := "search?" + params.Encode()
+	var out SearchResults
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return SearchResults{}, err
+	}
+
+	for i := range out.Boards {
+		out.Boards[i].client = c
+	}
+	for i := range out.Cards {
+		ca := &out.Cards[i]
+		if ca.Board.ID == "" {
+			ca.Board.ID = ca.IDBoard
+		}
+		ca.Board.client = c
+		if ca.List.ID == "" {
+			ca.List.ID = ca.IDList
+		}
+		if ca.List.IDBoard == "" {
+			ca.List.IDBoard = ca.IDBoard
+		}
+		ca.List.Board = ca.Board
+		ca.List.client = c
+		ca.client = c
+		for j := range ca.Labels {
+			ca.Labels[j].client = c
+		}
+	}
+	for i := range out.Members {
+		out.Members[i].client = c
+	}
+	for i := range out.Organizations {
+		out.Organizations[i].client = c
+	}
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package trel
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_Search(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var query url.Values
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		query.Del("key")
+		query.Del("token")
+		fmt.Fprint(w, `{
+			"boards": [{"id": "b1", "name": "Project"}],
+			"cards": [
+				{"id": "c1", "name": "Deploy", "idBoard": "b1", "idList": "l1",
+					"board": {"id": "b1", "name": "Project"}, "list": {"id": "l1", "name": "To Do"}},
+				{"id": "c2", "name": "Deploy docs", "idBoard": "b2", "idList": "l2", "labels": [{"id": "lb1"}]}],
+			"members": [{"id": "m1", "username": "alice"}],
+			"organizations": [{"id": "o1", "name": "acme"}]}`)
+	})
+
+	results, err := client.Search("deploy", SearchOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareQuery := url.Values{"query": {"deploy"}, "modelTypes": {"boards,cards,members,organizations"},
+		"partial": {"false"}, "card_board": {"true"}, "card_list": {"true"}}
+	if !reflect.DeepEqual(compareQuery, query) {
+		t.Errorf("Expected %#v, got %#v\n", compareQuery, query)
+	}
+
+	board1 := Board{ID: "b1", Name: "Project", client: client}
+	board2 := Board{ID: "b2", client: client}
+	compare := SearchResults{
+		Boards: Boards{board1},
+		Cards: Cards{
+			{ID: "c1", Name: "Deploy", IDBoard: "b1", IDList: "l1", Board: board1,
+				List: List{ID: "l1", Name: "To Do", IDBoard: "b1", Board: board1, client: client}, client: client},
+			{ID: "c2", Name: "Deploy docs", IDBoard: "b2", IDList: "l2", Board: board2, Labels: Labels{{ID: "lb1", client: client}},
+				List: List{ID: "l2", IDBoard: "b2", Board: board2, client: client}, client: client},
+		},
+		Members:       Members{{ID: "m1", Username: "alice", client: client}},
+		Organizations: Organizations{{ID: "o1", Name: "acme", client: client}},
+	}
+	if !reflect.DeepEqual(compare, results) {
+		t.Errorf("Expected %#v, got %#v\n", compare, results)
+	}
+}
+
+func TestClient_SearchOptions(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var query url.Values
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		query.Del("key")
+		query.Del("token")
+		fmt.Fprint(w, `{"cards": []}`)
+	})
+
+	options := SearchOptions{
+		ModelTypes:      []string{SearchCards, SearchBoards},
+		IDBoards:        []string{"b1", "b2"},
+		IDOrganizations: []string{"o1"},
+		Partial:         true,
+		Limit:           50,
+	}
+	if _, err := client.Search("dep", options); err != nil {
+		t.Fatal(err)
+	}
+
+	compareQuery := url.Values{"query": {"dep"}, "modelTypes": {"cards,boards"}, "idBoards": {"b1,b2"},
+		"idOrganizations": {"o1"}, "partial": {"true"}, "cards_limit": {"50"}, "boards_limit": {"50"},
+		"card_board": {"true"}, "card_list": {"true"}}
+	if !reflect.DeepEqual(compareQuery, query) {
+		t.Errorf("Expected %#v, got %#v\n", compareQuery, query)
+	}
+}
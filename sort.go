@@ -0,0 +1,141 @@
+package trel
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Trello spaces positions this far apart at the end of a list.
+const positionSpacing = 65536
+
+// CardOrder reports whether a should come before b.
+type CardOrder func(a, b Card) bool
+
+// ByName orders cards by name, ignoring case.
+func ByName(a, b Card) bool {
+	return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+}
+
+// ByDue orders cards by due date, with the cards without one last.
+func ByDue(a, b Card) bool {
+	if a.Due.IsZero() || b.Due.IsZero() {
+		return !a.Due.IsZero() && b.Due.IsZero()
+	}
+	return a.Due.Before(b.Due)
+}
+
+// ByLabel orders cards by the first of their label names, in alphabetical
+// order, with the cards without labels last.
+func ByLabel(a, b Card) bool {
+	la, lb := firstLabel(a), firstLabel(b)
+	if la == "" || lb == "" {
+		return la != "" && lb == ""
+	}
+	return la < lb
+}
+
+func firstLabel(ca Card) string {
+	first := ""
+	for _, l := range ca.Labels {
+		name := strings.ToLower(l.Name)
+		if name == "" {
+			name = l.Color
+		}
+		if first == "" || name < first {
+			first = name
+		}
+	}
+	return first
+}
+
+// ByCreation orders cards by the time they were created, oldest first.
+func ByCreation(a, b Card) bool {
+	return a.Created().Before(b.Created())
+}
+
+// Sort orders cards, the cards on the list, by the given order. cards is
+// sorted in place, and only the cards that are out of order are moved, so
+// that as few requests as possible are sent. Cards that compare equal keep
+// their current order.
+func (l List) Sort(cards Cards, by CardOrder) error {
+	return l.SortContext(context.Background(), cards, by)
+}
+
+func (l List) SortContext(ctx context.Context, cards Cards, by CardOrder) error {
+	// Start from the current order, so that equal cards stay where they are.
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+	sort.SliceStable(cards, func(i, j int) bool { return by(cards[i], cards[j]) })
+
+	c := l.client
+	for i, pos := range sortedPositions(cards) {
+		if pos == cards[i].Pos {
+			continue
+		}
+		apiurl := apiPath("cards", cards[i].ID)
+		body := fields{"pos": strconv.FormatFloat(pos, 'f', -1, 64)}
+		if err := c.doMethod(ctx, http.MethodPut, apiurl, body); err != nil {
+			return err
+		}
+		cards[i].Pos = pos
+	}
+	return nil
+}
+
+// sortedPositions returns increasing positions for cards, keeping the
+// positions of the longest run of cards that are already in order, and
+// putting the others in the gaps between them.
+func sortedPositions(cards Cards) []float64 {
+	n := len(cards)
+	if n == 0 {
+		return nil
+	}
+
+	// Find the longest increasing subsequence of the current positions.
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := 0
+	for i := range cards {
+		length[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if cards[j].Pos < cards[i].Pos && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if length[i] > length[best] {
+			best = i
+		}
+	}
+	keep := make([]bool, n)
+	for i := best; i != -1; i = prev[i] {
+		keep[i] = true
+	}
+
+	positions := make([]float64, n)
+	low := 0.0
+	for i := 0; i < n; {
+		if keep[i] {
+			positions[i] = cards[i].Pos
+			low = positions[i]
+			i++
+			continue
+		}
+
+		// Spread the run of cards up to the next kept card over the gap.
+		j := i
+		for j < n && !keep[j] {
+			j++
+		}
+		for k := i; k < j; k++ {
+			if j < n {
+				positions[k] = low + (cards[j].Pos-low)*float64(k-i+1)/float64(j-i+1)
+			} else {
+				positions[k] = low + positionSpacing*float64(k-i+1)
+			}
+		}
+		i = j
+	}
+	return positions
+}
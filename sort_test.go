@@ -0,0 +1,103 @@
+package trel
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSortedPositions(t *testing.T) {
+	cases := []struct {
+		Positions []float64
+		Expected  []float64
+	}{
+		{Positions: nil, Expected: nil},
+		{Positions: []float64{1, 2, 3}, Expected: []float64{1, 2, 3}},
+		{Positions: []float64{3, 1, 2}, Expected: []float64{0.5, 1, 2}},
+		{Positions: []float64{2, 3, 1}, Expected: []float64{2, 3, 3 + positionSpacing}},
+		{Positions: []float64{100, 400, 200, 300, 500}, Expected: []float64{100, 150, 200, 300, 500}},
+		{Positions: []float64{400, 300, 200, 100}, Expected: []float64{400, 400 + positionSpacing, 400 + 2*positionSpacing, 400 + 3*positionSpacing}},
+	}
+
+	for _, c := range cases {
+		cards := make(Cards, len(c.Positions))
+		for i, pos := range c.Positions {
+			cards[i].Pos = pos
+		}
+
+		positions := sortedPositions(cards)
+		if !reflect.DeepEqual(c.Expected, positions) {
+			t.Errorf("Expected %v, got %v\n", c.Expected, positions)
+		}
+		if !sort.Float64sAreSorted(positions) {
+			t.Errorf("Expected increasing positions, got %v\n", positions)
+		}
+	}
+}
+
+func TestList_Sort(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	due := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	cards := func() Cards {
+		return Cards{
+			{ID: "5e0d5a80aaaaaaaaaaaaaaaa", Name: "banana", Pos: 100, Due: due.Add(time.Hour),
+				Labels: Labels{{Name: "Bug"}}, client: client},
+			{ID: "5e0d5a00aaaaaaaaaaaaaaaa", Name: "Apple", Pos: 200,
+				Labels: Labels{{Name: "Feature"}, {Name: "Backend"}}, client: client},
+			{ID: "5e0d5b00aaaaaaaaaaaaaaaa", Name: "cherry", Pos: 300, Due: due, client: client},
+		}
+	}
+
+	cases := []struct {
+		By       CardOrder
+		Names    []string
+		Requests int
+	}{
+		{By: ByName, Names: []string{"Apple", "banana", "cherry"}, Requests: 1},
+		{By: ByDue, Names: []string{"cherry", "banana", "Apple"}, Requests: 1},
+		{By: ByLabel, Names: []string{"Apple", "banana", "cherry"}, Requests: 1},
+		{By: ByCreation, Names: []string{"Apple", "banana", "cherry"}, Requests: 1},
+	}
+
+	positions := map[string]string{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+		positions[r.URL.Path] = body["pos"]
+		fmt.Fprint(w, "{}")
+	})
+
+	list := List{ID: "l", client: client}
+	for _, c := range cases {
+		positions = map[string]string{}
+		sorted := cards()
+		if err := list.Sort(sorted, c.By); err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for i := range sorted {
+			names = append(names, sorted[i].Name)
+			if i > 0 && sorted[i-1].Pos >= sorted[i].Pos {
+				t.Errorf("Expected increasing positions, got %v and %v\n", sorted[i-1].Pos, sorted[i].Pos)
+			}
+		}
+		if !reflect.DeepEqual(c.Names, names) {
+			t.Errorf("Expected %v, got %v\n", c.Names, names)
+		}
+		if c.Requests != len(positions) {
+			t.Errorf("Expected %d requests, got %v\n", c.Requests, positions)
+		}
+		for i := range sorted {
+			if pos, ok := positions["/cards/"+sorted[i].ID]; ok && pos != fmt.Sprint(sorted[i].Pos) {
+				t.Errorf("Expected position %v, got %s\n", sorted[i].Pos, pos)
+			}
+		}
+	}
+}
@@ -1,11 +1,23 @@
 package trel
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 const defaultAPIPrefix = "https://api.trello.com/1/"
@@ -17,19 +29,35 @@ type Client struct {
 
 	APIKey string
 	Token  string
+
+	// AuthHeader sends the credentials in an OAuth Authorization header
+	// instead of the query string, which keeps them out of URLs, except for
+	// Webhooks, whose path has to contain the token.
+	AuthHeader bool
+
+	// Retry decides which failed requests are sent again. New sets it to
+	// DefaultRetryPolicy. Set it to the zero RetryPolicy to turn retrying off.
+	Retry RetryPolicy
+	// Limiter delays requests to stay within Trello's rate limits. New sets
+	// one with DefaultRateLimits for the key and token. Set it to nil to turn
+	// limiting off, or replace it if APIKey or Token change.
+	Limiter *RateLimiter
 }
 
 type Board struct {
-	ID     string `json:"id"`
-	Name   string `json:"name"`
-	client *Client
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Closed         bool   `json:"closed"`
+	IDOrganization string `json:"idOrganization"`
+	client         *Client
 }
 
 type List struct {
-	ID      string `json:"id"`
-	Name    string `json:"name"`
-	Closed  bool   `json:"closed"`
-	IDBoard string `json:"idBoard"`
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Closed  bool    `json:"closed"`
+	IDBoard string  `json:"idBoard"`
+	Pos     float64 `json:"pos"`
 	Board   Board
 	client  *Client
 }
@@ -42,9 +70,18 @@ type Card struct {
 	IDBoard      string   `json:"idBoard"`
 	IDChecklists []string `json:"idChecklists"`
 	IDList       string   `json:"idList"`
-	List         List
-	Board        Board
-	client       *Client
+	Pos          float64  `json:"pos"`
+	IDLabels     []string `json:"idLabels"`
+	Labels       Labels   `json:"labels"`
+	IDMembers    []string `json:"idMembers"`
+	// Due and Start are zero if they aren't set.
+	Due         time.Time `json:"due"`
+	Start       time.Time `json:"start"`
+	DueComplete bool      `json:"dueComplete"`
+	Badges      Badges    `json:"badges"`
+	List        List
+	Board       Board
+	client      *Client
 }
 
 type Checklist struct {
@@ -59,14 +96,38 @@ type Checklist struct {
 }
 
 type CheckItem struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	State       string `json:"state"` // TODO: Turn this into a boolean type and add custom json parsing.
-	IDChecklist string `json:"idChecklist"`
+	ID          string         `json:"id"`
+	Name        string         `json:"name"`
+	State       CheckItemState `json:"state"`
+	IDChecklist string         `json:"idChecklist"`
+	Pos         float64        `json:"pos"`
 	Checklist   Checklist
 	client      *Client
 }
 
+// CheckItemState is whether a CheckItem is complete. It's sent to and from
+// Trello as "complete" or "incomplete".
+type CheckItemState bool
+
+const (
+	StateIncomplete CheckItemState = false
+	StateComplete   CheckItemState = true
+)
+
+// Progress is how many of a number of check items are complete.
+type Progress struct {
+	Completed int
+	Total     int
+}
+
+// Badges are Trello's summary of a card's contents.
+type Badges struct {
+	CheckItems        int `json:"checkItems"`
+	CheckItemsChecked int `json:"checkItemsChecked"`
+	Comments          int `json:"comments"`
+	Attachments       int `json:"attachments"`
+}
+
 type Webhook struct {
 	ID          string `json:"id"`
 	Description string `json:"description"`
@@ -76,12 +137,46 @@ type Webhook struct {
 	client      *Client
 }
 
+type Label struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Color   string `json:"color"`
+	IDBoard string `json:"idBoard"`
+	Board   Board
+	client  *Client
+}
+
+type Member struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+	Initials string `json:"initials"`
+	client   *Client
+}
+
+// Attachment is a file uploaded to a card, or a link attached to it.
+type Attachment struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	URL      string    `json:"url"`
+	MimeType string    `json:"mimeType"`
+	Bytes    int64     `json:"bytes"`
+	Date     time.Time `json:"date"`
+	IsUpload bool      `json:"isUpload"`
+	IDMember string    `json:"idMember"`
+	Card     Card
+	client   *Client
+}
+
 type Boards []Board
 type Lists []List
 type Cards []Card
 type Checklists []Checklist
 type CheckItems []CheckItem
 type Webhooks []Webhook
+type Labels []Label
+type Members []Member
+type Attachments []Attachment
 
 func New(client *http.Client, apiKey, token string) *Client {
 	if client == nil {
@@ -95,13 +190,19 @@ func New(client *http.Client, apiKey, token string) *Client {
 		BaseURL: baseURL,
 		APIKey:  apiKey,
 		Token:   token,
+		Retry:   DefaultRetryPolicy,
+		Limiter: NewRateLimiter(apiKey, token, DefaultRateLimits),
 	}
 }
 
 func (c *Client) Boards(username string) (Boards, error) {
-	apiurl := fmt.Sprintf("members/%s/boards?key=%s&token=%s", username, c.APIKey, c.Token)
+	return c.BoardsContext(context.Background(), username)
+}
+
+func (c *Client) BoardsContext(ctx context.Context, username string) (Boards, error) {
+	apiurl := apiPath("members", username, "boards")
 	var out Boards
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return nil, err
 	}
 	for i := range out {
@@ -111,9 +212,55 @@ func (c *Client) Boards(username string) (Boards, error) {
 }
 
 func (c *Client) Board(id string) (Board, error) {
-	apiurl := fmt.Sprintf("boards/%s?key=%s&token=%s", id, c.APIKey, c.Token)
+	return c.BoardContext(context.Background(), id)
+}
+
+func (c *Client) BoardContext(ctx context.Context, id string) (Board, error) {
+	apiurl := apiPath("boards", id)
+	var out Board
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return Board{}, err
+	}
+	out.client = c
+	return out, nil
+}
+
+// BoardOptions are the settings of a new board.
+type BoardOptions struct {
+	// DefaultLists creates Trello's To Do, Doing and Done lists on the board.
+	DefaultLists bool
+	// IDOrganization is the organization (Workspace) the board belongs to.
+	IDOrganization string
+	// PermissionLevel is "private", "org" or "public", Trello's default if
+	// it's empty.
+	PermissionLevel string
+	// IDBoardSource is a board to copy, like a template. Its lists are
+	// copied, and its cards too if KeepCards is set.
+	IDBoardSource string
+	KeepCards     bool
+}
+
+func (c *Client) NewBoard(name string, options BoardOptions) (Board, error) {
+	return c.NewBoardContext(context.Background(), name, options)
+}
+
+func (c *Client) NewBoardContext(ctx context.Context, name string, options BoardOptions) (Board, error) {
+	body := fields{"name": name, "defaultLists": options.DefaultLists}
+	if options.IDOrganization != "" {
+		body["idOrganization"] = options.IDOrganization
+	}
+	if options.PermissionLevel != "" {
+		body["prefs_permissionLevel"] = options.PermissionLevel
+	}
+	if options.IDBoardSource != "" {
+		body["idBoardSource"] = options.IDBoardSource
+		body["keepFromSource"] = "none"
+		if options.KeepCards {
+			body["keepFromSource"] = "cards"
+		}
+	}
 	var out Board
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, "boards", body, &out); err != nil {
 		return Board{}, err
 	}
 	out.client = c
@@ -121,9 +268,13 @@ func (c *Client) Board(id string) (Board, error) {
 }
 
 func (c *Client) List(id string) (List, error) {
-	apiurl := fmt.Sprintf("lists/%s?key=%s&token=%s", id, c.APIKey, c.Token)
+	return c.ListContext(context.Background(), id)
+}
+
+func (c *Client) ListContext(ctx context.Context, id string) (List, error) {
+	apiurl := apiPath("lists", id)
 	var out List
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return List{}, err
 	}
 	out.client = c
@@ -131,19 +282,30 @@ func (c *Client) List(id string) (List, error) {
 }
 
 func (c *Client) Card(id string) (Card, error) {
-	apiurl := fmt.Sprintf("cards/%s?key=%s&token=%s", id, c.APIKey, c.Token)
+	return c.CardContext(context.Background(), id)
+}
+
+func (c *Client) CardContext(ctx context.Context, id string) (Card, error) {
+	apiurl := apiPath("cards", id)
 	var out Card
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return Card{}, err
 	}
 	out.client = c
+	for i := range out.Labels {
+		out.Labels[i].client = c
+	}
 	return out, nil
 }
 
 func (c *Client) Checklist(id string) (Checklist, error) {
-	apiurl := fmt.Sprintf("checklists/%s?key=%s&token=%s", id, c.APIKey, c.Token)
+	return c.ChecklistContext(context.Background(), id)
+}
+
+func (c *Client) ChecklistContext(ctx context.Context, id string) (Checklist, error) {
+	apiurl := apiPath("checklists", id)
 	var out Checklist
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return Checklist{}, err
 	}
 	out.client = c
@@ -154,21 +316,55 @@ func (c *Client) Checklist(id string) (Checklist, error) {
 	return out, nil
 }
 
+// Member gets a member by id or username.
+func (c *Client) Member(idOrUsername string) (Member, error) {
+	return c.MemberContext(context.Background(), idOrUsername)
+}
+
+func (c *Client) MemberContext(ctx context.Context, idOrUsername string) (Member, error) {
+	apiurl := apiPath("members", idOrUsername)
+	var out Member
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return Member{}, err
+	}
+	out.client = c
+	return out, nil
+}
+
+// Me gets the member the token belongs to.
+func (c *Client) Me() (Member, error) {
+	return c.MeContext(context.Background())
+}
+
+func (c *Client) MeContext(ctx context.Context) (Member, error) {
+	return c.MemberContext(ctx, "me")
+}
+
 func (c *Client) NewWebhook(description, callbackURL, idModel string) (Webhook, error) {
-	description, callbackURL = url.QueryEscape(description), url.QueryEscape(callbackURL)
-	apiurl := fmt.Sprintf("webhooks/?description=%s&callbackURL=%s&idModel=%s&key=%s&token=%s", description, callbackURL, idModel, c.APIKey, c.Token)
+	return c.NewWebhookContext(context.Background(), description, callbackURL, idModel)
+}
+
+func (c *Client) NewWebhookContext(ctx context.Context, description, callbackURL, idModel string) (Webhook, error) {
+	body := fields{"description": description, "callbackURL": callbackURL, "idModel": idModel}
 	var out Webhook
-	if err := c.doMethodAndParseBody(http.MethodPost, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, "webhooks", body, &out); err != nil {
 		return Webhook{}, err
 	}
 	out.client = c
 	return out, nil
 }
 
+// Webhooks returns the webhooks made with the Client's token. Trello only
+// lists them by token, so the token is part of the URL even if AuthHeader
+// is set. HTTPRequestError redacts it from the path.
 func (c *Client) Webhooks() (Webhooks, error) {
-	apiurl := fmt.Sprintf("tokens/%s/webhooks?key=%s", c.Token, c.APIKey)
+	return c.WebhooksContext(context.Background())
+}
+
+func (c *Client) WebhooksContext(ctx context.Context) (Webhooks, error) {
+	apiurl := apiPath("tokens", c.Token, "webhooks")
 	var out Webhooks
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return nil, err
 	}
 	for i := range out {
@@ -178,20 +374,84 @@ func (c *Client) Webhooks() (Webhooks, error) {
 }
 
 func (c *Client) Webhook(id string) (Webhook, error) {
-	apiurl := fmt.Sprintf("webhooks/%s?key=%s&token=%s", id, c.APIKey, c.Token)
+	return c.WebhookContext(context.Background(), id)
+}
+
+func (c *Client) WebhookContext(ctx context.Context, id string) (Webhook, error) {
+	apiurl := apiPath("webhooks", id)
 	var out Webhook
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return Webhook{}, err
 	}
 	out.client = c
 	return out, nil
 }
 
+// EnsureWebhook makes sure there is exactly one active webhook for idModel,
+// with the given callbackURL and description, and returns it. An existing
+// webhook for idModel is reused, preferably one that already has callbackURL,
+// and updated if needed. Any other webhooks for idModel are deleted after
+// that, so they are left alone if the update fails. Like Webhooks, it puts
+// the token in a URL even if AuthHeader is set.
+func (c *Client) EnsureWebhook(idModel, callbackURL, description string) (Webhook, error) {
+	return c.EnsureWebhookContext(context.Background(), idModel, callbackURL, description)
+}
+
+func (c *Client) EnsureWebhookContext(ctx context.Context, idModel, callbackURL, description string) (Webhook, error) {
+	webhooks, err := c.WebhooksContext(ctx)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	var matches Webhooks
+	keep := -1
+	for _, w := range webhooks {
+		if w.IDModel != idModel {
+			continue
+		}
+		if keep == -1 && w.CallbackURL == callbackURL {
+			keep = len(matches)
+		}
+		matches = append(matches, w)
+	}
+	if len(matches) == 0 {
+		return c.NewWebhookContext(ctx, description, callbackURL, idModel)
+	}
+	if keep == -1 {
+		keep = 0
+	}
+
+	// Update the kept webhook first, so that a failed update leaves the
+	// others in place.
+	out := matches[keep]
+	if out.CallbackURL != callbackURL || out.Description != description || !out.Active {
+		body := fields{"callbackURL": callbackURL, "description": description, "active": true}
+		if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiPath("webhooks", out.ID), body, &out); err != nil {
+			return Webhook{}, err
+		}
+		out.client = c
+	}
+
+	for i := range matches {
+		if i == keep {
+			continue
+		}
+		if err := matches[i].DeleteContext(ctx); err != nil {
+			return Webhook{}, err
+		}
+	}
+	return out, nil
+}
+
 func (b Board) Lists() (Lists, error) {
+	return b.ListsContext(context.Background())
+}
+
+func (b Board) ListsContext(ctx context.Context) (Lists, error) {
 	c := b.client
-	apiurl := fmt.Sprintf("boards/%s/lists?key=%s&token=%s", b.ID, c.APIKey, c.Token)
+	apiurl := apiPath("boards", b.ID, "lists")
 	var out Lists
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return nil, err
 	}
 	for i := range out {
@@ -202,15 +462,17 @@ func (b Board) Lists() (Lists, error) {
 }
 
 func (b Board) NewList(name, position string) (List, error) {
+	return b.NewListContext(context.Background(), name, position)
+}
+
+func (b Board) NewListContext(ctx context.Context, name, position string) (List, error) {
 	c := b.client
 	if position == "" {
 		position = "bottom"
 	}
-	// TODO: Handle query arguments and escaping better, probably use url.URL.
-	name, position = url.QueryEscape(name), url.QueryEscape(position)
-	apiurl := fmt.Sprintf("boards/%s/lists?name=%s&pos=%s&key=%s&token=%s", b.ID, name, position, c.APIKey, c.Token)
+	apiurl := apiPath("boards", b.ID, "lists")
 	var out List
-	if err := c.doMethodAndParseBody(http.MethodPost, apiurl, &out); err != nil {
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, fields{"name": name, "pos": position}, &out); err != nil {
 		return List{}, err
 	}
 	out.client = c
@@ -219,7 +481,11 @@ func (b Board) NewList(name, position string) (List, error) {
 }
 
 func (b Board) FindList(name string) (List, error) {
-	lists, err := b.Lists()
+	return b.FindListContext(context.Background(), name)
+}
+
+func (b Board) FindListContext(ctx context.Context, name string) (List, error) {
+	lists, err := b.ListsContext(ctx)
 	if err != nil {
 		return List{}, err
 	}
@@ -227,245 +493,1701 @@ func (b Board) FindList(name string) (List, error) {
 	return *l, err
 }
 
-func (l List) Cards() (Cards, error) {
-	c := l.client
-	apiurl := fmt.Sprintf("lists/%s/cards?key=%s&token=%s", l.ID, c.APIKey, c.Token)
-	var out Cards
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+func (b Board) Labels() (Labels, error) {
+	return b.LabelsContext(context.Background())
+}
+
+func (b Board) LabelsContext(ctx context.Context) (Labels, error) {
+	c := b.client
+	apiurl := apiPath("boards", b.ID, "labels")
+	var out Labels
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return nil, err
 	}
 	for i := range out {
-		out[i].Board = l.Board
-		out[i].List = l
+		out[i].Board = b
 		out[i].client = c
 	}
 	return out, nil
 }
 
-func (l List) FindCard(name string) (Card, error) {
-	cards, err := l.Cards()
-	if err != nil {
-		return Card{}, err
-	}
-	c, err := cards.Find(name)
-	return *c, err
+func (b Board) NewLabel(name, color string) (Label, error) {
+	return b.NewLabelContext(context.Background(), name, color)
 }
 
-func (l List) NewCard(name, desc, position string) (Card, error) {
-	c := l.client
-	name, desc, position = url.QueryEscape(name), url.QueryEscape(desc), url.QueryEscape(position)
-	query := fmt.Sprintf("idList=%s&name=%s&desc=%s&pos=%s&key=%s&token=%s", l.ID, name, desc, position, c.APIKey, c.Token)
-	apiurl := "cards?" + query
-	var out Card
-	if err := c.doMethodAndParseBody(http.MethodPost, apiurl, &out); err != nil {
-		return Card{}, err
+func (b Board) NewLabelContext(ctx context.Context, name, color string) (Label, error) {
+	c := b.client
+	apiurl := apiPath("boards", b.ID, "labels")
+	var out Label
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, fields{"name": name, "color": color}, &out); err != nil {
+		return Label{}, err
 	}
-	out.Board = l.Board
-	out.List = l
+	out.Board = b
 	out.client = c
 	return out, nil
 }
 
-func (ls Lists) Find(name string) (*List, error) {
-	for i := range ls {
-		if ls[i].Name == name {
-			return &ls[i], nil
-		}
+func (b Board) FindLabel(name string) (Label, error) {
+	return b.FindLabelContext(context.Background(), name)
+}
+
+func (b Board) FindLabelContext(ctx context.Context, name string) (Label, error) {
+	labels, err := b.LabelsContext(ctx)
+	if err != nil {
+		return Label{}, err
 	}
-	return &List{}, NotFoundError{Type: "List", Identifier: name}
+	l, err := labels.Find(name)
+	return *l, err
 }
 
-func (ca *Card) Move(listID string) error {
-	// Don't do anything if the card is already on that list.
-	if ca.IDList == listID {
+func (b Board) Members() (Members, error) {
+	return b.MembersContext(context.Background())
+}
+
+func (b Board) MembersContext(ctx context.Context) (Members, error) {
+	c := b.client
+	apiurl := apiPath("boards", b.ID, "members")
+	var out Members
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].client = c
+	}
+	return out, nil
+}
+
+func (b *Board) Rename(name string) error {
+	return b.RenameContext(context.Background(), name)
+}
+
+func (b *Board) RenameContext(ctx context.Context, name string) error {
+	if b.Name == name {
 		return nil
 	}
 
-	c := ca.client
-	apiurl := fmt.Sprintf("cards/%s?idList=%s&key=%s&token=%s", ca.ID, listID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
+	c := b.client
+	apiurl := apiPath("boards", b.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name}); err != nil {
 		return err
 	}
-	// TODO: Eventually handle List and ListID mismatch in a better way.
-	ca.IDList = listID
-	ca.List.ID = listID
+	b.Name = name
 	return nil
 }
 
-func (ca *Card) Rename(name string) error {
-	if ca.Name == name {
+// Close closes the board, which archives it. Closed boards can be reopened.
+func (b *Board) Close() error {
+	return b.CloseContext(context.Background())
+}
+
+func (b *Board) CloseContext(ctx context.Context) error {
+	return b.setClosed(ctx, true)
+}
+
+func (b *Board) Reopen() error {
+	return b.ReopenContext(context.Background())
+}
+
+func (b *Board) ReopenContext(ctx context.Context) error {
+	return b.setClosed(ctx, false)
+}
+
+func (b *Board) setClosed(ctx context.Context, closed bool) error {
+	if b.Closed == closed {
 		return nil
 	}
 
-	c := ca.client
-	escapedName := url.QueryEscape(name)
-	apiurl := fmt.Sprintf("cards/%s?name=%s&key=%s&token=%s", ca.ID, escapedName, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
+	c := b.client
+	apiurl := apiPath("boards", b.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"closed": closed}); err != nil {
 		return err
 	}
-	ca.Name = name
+	b.Closed = closed
 	return nil
 }
 
-func (ca *Card) Checklists() (Checklists, error) {
-	c := ca.client
-	apiurl := fmt.Sprintf("cards/%s/checklists?key=%s&token=%s", ca.ID, c.APIKey, c.Token)
-	var out Checklists
-	if err := c.doMethodAndParseBody(http.MethodGet, apiurl, &out); err != nil {
+// Delete deletes the board and everything on it for good. Use Close to keep
+// it.
+func (b *Board) Delete() error {
+	return b.DeleteContext(context.Background())
+}
+
+func (b *Board) DeleteContext(ctx context.Context) error {
+	c := b.client
+	apiurl := apiPath("boards", b.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*b = Board{}
+	return nil
+}
+
+func (l List) Cards() (Cards, error) {
+	return l.CardsContext(context.Background())
+}
+
+func (l List) CardsContext(ctx context.Context) (Cards, error) {
+	c := l.client
+	apiurl := apiPath("lists", l.ID, "cards")
+	var out Cards
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
 		return nil, err
 	}
 	for i := range out {
-		out[i].Card = *ca
-		out[i].Board = ca.Board
+		out[i].Board = l.Board
+		out[i].List = l
 		out[i].client = c
-		for j := range out[i].CheckItems {
-			// Properly set the Checklist for every CheckItem.
-			out[i].CheckItems[j].Checklist = out[i]
-			out[i].CheckItems[j].client = c
+		for j := range out[i].Labels {
+			out[i].Labels[j].client = c
 		}
 	}
 	return out, nil
 }
 
-func (cs Cards) Find(name string) (*Card, error) {
-	for i := range cs {
-		if cs[i].Name == name {
-			return &cs[i], nil
-		}
-	}
-	return &Card{}, NotFoundError{Type: "Card", Identifier: name}
+func (l List) FindCard(name string) (Card, error) {
+	return l.FindCardContext(context.Background(), name)
 }
 
-func (ci *CheckItem) Complete() error {
-	c := ci.client
-	apiurl := fmt.Sprintf("cards/%s/checkItem/%s?state=complete&key=%s&token=%s", ci.Checklist.IDCard, ci.ID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
-		return err
+func (l List) FindCardContext(ctx context.Context, name string) (Card, error) {
+	cards, err := l.CardsContext(ctx)
+	if err != nil {
+		return Card{}, err
 	}
-	ci.State = "complete"
-	return nil
+	c, err := cards.Find(name)
+	return *c, err
 }
 
-func (ci *CheckItem) Incomplete() error {
-	c := ci.client
-	apiurl := fmt.Sprintf("cards/%s/checkItem/%s?state=incomplete&key=%s&token=%s", ci.Checklist.IDCard, ci.ID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
-		return err
-	}
-	ci.State = "incomplete"
-	return nil
+func (l List) NewCard(name, desc, position string) (Card, error) {
+	return l.NewCardContext(context.Background(), name, desc, position)
 }
 
-func (cis CheckItems) Find(name string) (*CheckItem, error) {
-	for i := range cis {
-		if cis[i].Name == name {
-			return &cis[i], nil
-		}
+func (l List) NewCardContext(ctx context.Context, name, desc, position string) (Card, error) {
+	c := l.client
+	body := fields{"idList": l.ID, "name": name, "desc": desc}
+	if position != "" {
+		body["pos"] = position
 	}
-	return &CheckItem{}, NotFoundError{Type: "CheckItem", Identifier: name}
+	var out Card
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, "cards", body, &out); err != nil {
+		return Card{}, err
+	}
+	out.Board = l.Board
+	out.List = l
+	out.client = c
+	for i := range out.Labels {
+		out.Labels[i].client = c
+	}
+	return out, nil
 }
 
-func (ci *CheckItem) Rename(name string) error {
-	c := ci.client
-	escapedName := url.QueryEscape(name)
-	apiurl := fmt.Sprintf("cards/%s/checkItem/%s?name=%s&key=%s&token=%s", ci.Checklist.IDCard, ci.ID, escapedName, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
-		return err
-	}
-	ci.Name = name
-	return nil
+func (l *List) Rename(name string) error {
+	return l.RenameContext(context.Background(), name)
 }
 
-func (w *Webhook) Activate() error {
-	// Don't activate active webhooks.
-	if w.Active {
+func (l *List) RenameContext(ctx context.Context, name string) error {
+	if l.Name == name {
 		return nil
 	}
 
-	c := w.client
-	apiurl := fmt.Sprintf("webhooks/%s?active=true&key=%s&token=%s", w.ID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
+	c := l.client
+	apiurl := apiPath("lists", l.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name}); err != nil {
 		return err
 	}
-	w.Active = true
+	l.Name = name
 	return nil
 }
 
-func (w *Webhook) Deactivate() error {
-	// Don't deactivate inactive webhooks.
-	if !w.Active {
+// Archive closes the list. The cards on it aren't archived, but are hidden
+// with it; use ArchiveAllCards to archive them.
+func (l *List) Archive() error {
+	return l.ArchiveContext(context.Background())
+}
+
+func (l *List) ArchiveContext(ctx context.Context) error {
+	return l.setClosed(ctx, true)
+}
+
+func (l *List) Unarchive() error {
+	return l.UnarchiveContext(context.Background())
+}
+
+func (l *List) UnarchiveContext(ctx context.Context) error {
+	return l.setClosed(ctx, false)
+}
+
+func (l *List) setClosed(ctx context.Context, closed bool) error {
+	if l.Closed == closed {
 		return nil
 	}
 
-	c := w.client
-	apiurl := fmt.Sprintf("webhooks/%s?active=false&key=%s&token=%s", w.ID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodPut, apiurl); err != nil {
+	c := l.client
+	apiurl := apiPath("lists", l.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"closed": closed}); err != nil {
 		return err
 	}
-	w.Active = false
+	l.Closed = closed
 	return nil
 }
 
-func (w *Webhook) Delete() error {
-	c := w.client
-	apiurl := fmt.Sprintf("webhooks/%s?key=%s&token=%s", w.ID, c.APIKey, c.Token)
-	if err := c.doMethod(http.MethodDelete, apiurl); err != nil {
+// SetPosition moves the list on its board, to "top", "bottom" or a number.
+func (l *List) SetPosition(position string) error {
+	return l.SetPositionContext(context.Background(), position)
+}
+
+func (l *List) SetPositionContext(ctx context.Context, position string) error {
+	c := l.client
+	apiurl := apiPath("lists", l.ID)
+	var out List
+	if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiurl, fields{"pos": position}, &out); err != nil {
 		return err
 	}
-	*w = Webhook{}
+	l.Pos = out.Pos
 	return nil
 }
 
-func (ws Webhooks) Find(modelID string) (*Webhook, error) {
-	for i := range ws {
-		if ws[i].IDModel == modelID {
+// MoveToBoard moves the list, with its cards, to board at position: "top",
+// "bottom" or a number. An empty position leaves it to Trello.
+func (l *List) MoveToBoard(board Board, position string) error {
+	return l.MoveToBoardContext(context.Background(), board, position)
+}
+
+func (l *List) MoveToBoardContext(ctx context.Context, board Board, position string) error {
+	c := l.client
+	body := fields{"idBoard": board.ID}
+	if position != "" {
+		body["pos"] = position
+	}
+	apiurl := apiPath("lists", l.ID)
+	var out List
+	if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiurl, body, &out); err != nil {
+		return err
+	}
+	l.IDBoard = board.ID
+	l.Board = board
+	l.Pos = out.Pos
+	return nil
+}
+
+// MoveAllCards moves every card on the list to the list to, which may be on
+// another board, and returns the moved cards. If from and into aren't nil,
+// they're updated as well: the moved cards are removed from from, and added
+// to into.
+func (l List) MoveAllCards(to List, from, into *Cards) (Cards, error) {
+	return l.MoveAllCardsContext(context.Background(), to, from, into)
+}
+
+func (l List) MoveAllCardsContext(ctx context.Context, to List, from, into *Cards) (Cards, error) {
+	c := l.client
+	// Trello needs the board, which is this one unless to says otherwise.
+	idBoard := to.boardID()
+	if idBoard == "" {
+		idBoard = l.boardID()
+	}
+	body := fields{"idBoard": idBoard, "idList": to.ID}
+	apiurl := apiPath("lists", l.ID, "moveAllCards")
+	var out Cards
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, body, &out); err != nil {
+		return nil, err
+	}
+
+	moved := map[string]bool{}
+	for i := range out {
+		out[i].Board = to.Board
+		out[i].List = to
+		out[i].client = c
+		for j := range out[i].Labels {
+			out[i].Labels[j].client = c
+		}
+		moved[out[i].ID] = true
+	}
+	if from != nil {
+		kept := (*from)[:0]
+		for _, ca := range *from {
+			if !moved[ca.ID] {
+				kept = append(kept, ca)
+			}
+		}
+		*from = kept
+	}
+	if into != nil {
+		*into = append(*into, out...)
+	}
+	return out, nil
+}
+
+// ArchiveAllCards archives every card on the list, and returns the archived
+// cards. The cards in cards, which may be nil, are marked as closed.
+func (l List) ArchiveAllCards(cards Cards) (Cards, error) {
+	return l.ArchiveAllCardsContext(context.Background(), cards)
+}
+
+func (l List) ArchiveAllCardsContext(ctx context.Context, cards Cards) (Cards, error) {
+	// Trello doesn't say which cards it archived, so get them first.
+	out, err := l.CardsContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := l.client
+	apiurl := apiPath("lists", l.ID, "archiveAllCards")
+	if err := c.doMethod(ctx, http.MethodPost, apiurl, nil); err != nil {
+		return nil, err
+	}
+
+	archived := map[string]bool{}
+	for i := range out {
+		out[i].Closed = true
+		archived[out[i].ID] = true
+	}
+	for i := range cards {
+		if archived[cards[i].ID] {
+			cards[i].Closed = true
+		}
+	}
+	return out, nil
+}
+
+// boardID is the ID of the list's board, if it's known.
+func (l List) boardID() string {
+	if l.IDBoard != "" {
+		return l.IDBoard
+	}
+	return l.Board.ID
+}
+
+func (ls Lists) Find(name string) (*List, error) {
+	for i := range ls {
+		if ls[i].Name == name {
+			return &ls[i], nil
+		}
+	}
+	return &List{}, NotFoundError{Type: "List", Identifier: name}
+}
+
+// Move moves the card to the list with listID, which may be on another board.
+// Use MoveTo to choose the position.
+func (ca *Card) Move(listID string) error {
+	return ca.MoveContext(context.Background(), listID)
+}
+
+func (ca *Card) MoveContext(ctx context.Context, listID string) error {
+	// Don't do anything if the card is already on that list.
+	if ca.IDList == listID {
+		return nil
+	}
+
+	// The list may be on another board, which MoveTo takes from the response.
+	return ca.MoveToContext(ctx, List{ID: listID}, "")
+}
+
+// MoveTo moves the card to list, which may be on another board, at position:
+// "top", "bottom" or a number. An empty position leaves it to Trello. IDBoard,
+// Board, IDList and List are all updated.
+func (ca *Card) MoveTo(list List, position string) error {
+	return ca.MoveToContext(context.Background(), list, position)
+}
+
+func (ca *Card) MoveToContext(ctx context.Context, list List, position string) error {
+	c := ca.client
+	body := fields{"idList": list.ID}
+	if idBoard := list.boardID(); idBoard != "" {
+		body["idBoard"] = idBoard
+	}
+	if position != "" {
+		body["pos"] = position
+	}
+	apiurl := apiPath("cards", ca.ID)
+	var out Card
+	if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiurl, body, &out); err != nil {
+		return err
+	}
+
+	ca.IDList = list.ID
+	ca.List = list
+	ca.Pos = out.Pos
+	if out.IDBoard != "" {
+		ca.IDBoard = out.IDBoard
+	} else if idBoard := list.boardID(); idBoard != "" {
+		ca.IDBoard = idBoard
+	}
+	if ca.Board.ID != ca.IDBoard {
+		if list.Board.ID == ca.IDBoard {
+			ca.Board = list.Board
+		} else {
+			ca.Board = Board{ID: ca.IDBoard, client: c}
+		}
+		// Labels belong to a board, so Trello changes them on the way.
+		ca.IDLabels = out.IDLabels
+		ca.Labels = out.Labels
+		for i := range ca.Labels {
+			ca.Labels[i].client = c
+		}
+	}
+	ca.List.IDBoard = ca.IDBoard
+	ca.List.Board = ca.Board
+	ca.List.client = c
+	return nil
+}
+
+// MoveAfter moves the card right after other, on other's list.
+func (ca *Card) MoveAfter(other Card) error {
+	return ca.MoveAfterContext(context.Background(), other)
+}
+
+func (ca *Card) MoveAfterContext(ctx context.Context, other Card) error {
+	list := other.List
+	if list.ID != other.IDList && other.IDList != "" {
+		list = List{ID: other.IDList, IDBoard: other.IDBoard, Board: other.Board}
+	}
+	if list.IDBoard == "" {
+		list.IDBoard = other.IDBoard
+	}
+	list.client = ca.client
+
+	cards, err := list.CardsContext(ctx)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+
+	// The card itself doesn't count as a neighbour if it's on the list already.
+	for i := 0; i < len(cards); i++ {
+		if cards[i].ID == ca.ID {
+			cards = append(cards[:i], cards[i+1:]...)
+			break
+		}
+	}
+	for i := range cards {
+		if cards[i].ID != other.ID {
+			continue
+		}
+		position := "bottom"
+		if i+1 < len(cards) {
+			position = strconv.FormatFloat((cards[i].Pos+cards[i+1].Pos)/2, 'f', -1, 64)
+		}
+		return ca.MoveToContext(ctx, list, position)
+	}
+	return NotFoundError{Type: "Card", Identifier: other.ID}
+}
+
+func (ca *Card) Rename(name string) error {
+	return ca.RenameContext(context.Background(), name)
+}
+
+func (ca *Card) RenameContext(ctx context.Context, name string) error {
+	if ca.Name == name {
+		return nil
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name}); err != nil {
+		return err
+	}
+	ca.Name = name
+	return nil
+}
+
+// SetDue sets the card's due date. A zero due clears it, like ClearDue.
+func (ca *Card) SetDue(due time.Time) error {
+	return ca.SetDueContext(context.Background(), due)
+}
+
+func (ca *Card) SetDueContext(ctx context.Context, due time.Time) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"due": timeValue(due)}); err != nil {
+		return err
+	}
+	ca.Due = due
+	return nil
+}
+
+func (ca *Card) ClearDue() error {
+	return ca.SetDueContext(context.Background(), time.Time{})
+}
+
+func (ca *Card) ClearDueContext(ctx context.Context) error {
+	return ca.SetDueContext(ctx, time.Time{})
+}
+
+// SetStart sets the card's start date. A zero start clears it.
+func (ca *Card) SetStart(start time.Time) error {
+	return ca.SetStartContext(context.Background(), start)
+}
+
+func (ca *Card) SetStartContext(ctx context.Context, start time.Time) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"start": timeValue(start)}); err != nil {
+		return err
+	}
+	ca.Start = start
+	return nil
+}
+
+// MarkDueComplete marks the card's due date as complete, or as not complete.
+func (ca *Card) MarkDueComplete(complete bool) error {
+	return ca.MarkDueCompleteContext(context.Background(), complete)
+}
+
+func (ca *Card) MarkDueCompleteContext(ctx context.Context, complete bool) error {
+	if ca.DueComplete == complete {
+		return nil
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"dueComplete": complete}); err != nil {
+		return err
+	}
+	ca.DueComplete = complete
+	return nil
+}
+
+// Archive closes the card, which hides it from its list but keeps it.
+func (ca *Card) Archive() error {
+	return ca.ArchiveContext(context.Background())
+}
+
+func (ca *Card) ArchiveContext(ctx context.Context) error {
+	return ca.setClosed(ctx, true)
+}
+
+// Unarchive reopens an archived card.
+func (ca *Card) Unarchive() error {
+	return ca.UnarchiveContext(context.Background())
+}
+
+func (ca *Card) UnarchiveContext(ctx context.Context) error {
+	return ca.setClosed(ctx, false)
+}
+
+func (ca *Card) setClosed(ctx context.Context, closed bool) error {
+	if ca.Closed == closed {
+		return nil
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"closed": closed}); err != nil {
+		return err
+	}
+	ca.Closed = closed
+	return nil
+}
+
+// Delete deletes the card for good. Use Archive to keep it.
+func (ca *Card) Delete() error {
+	return ca.DeleteContext(context.Background())
+}
+
+func (ca *Card) DeleteContext(ctx context.Context) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*ca = Card{}
+	return nil
+}
+
+// CopyOptions chooses what a copy of a card keeps. The name and description
+// are always copied.
+type CopyOptions struct {
+	// Name is the name of the copy, the card's name if it's empty.
+	Name string
+	// Position is "top", "bottom" or a number, "bottom" if it's empty.
+	Position    string
+	Checklists  bool
+	Labels      bool
+	Attachments bool
+	Comments    bool
+	Members     bool
+	Due         bool
+	Start       bool
+}
+
+// keepFromSource is the value Trello expects for the parts to keep, empty if
+// none of them are kept.
+func (o CopyOptions) keepFromSource() string {
+	var keep []string
+	for _, part := range []struct {
+		keep bool
+		name string
+	}{
+		{o.Checklists, "checklists"},
+		{o.Labels, "labels"},
+		{o.Attachments, "attachments"},
+		{o.Comments, "comments"},
+		{o.Members, "members"},
+		{o.Due, "due"},
+		{o.Start, "start"},
+	} {
+		if part.keep {
+			keep = append(keep, part.name)
+		}
+	}
+	return strings.Join(keep, ",")
+}
+
+// Copy creates a copy of the card on toList, keeping the parts chosen in
+// options, and returns the copy. If no parts are chosen, the copy is a new
+// card with the Name and Description of ca, as they were loaded.
+func (ca *Card) Copy(toList List, options CopyOptions) (Card, error) {
+	return ca.CopyContext(context.Background(), toList, options)
+}
+
+func (ca *Card) CopyContext(ctx context.Context, toList List, options CopyOptions) (Card, error) {
+	c := ca.client
+	keep := options.keepFromSource()
+	// Trello copies everything without keepFromSource, and has no value for
+	// nothing, so create a new card instead.
+	if keep == "" {
+		name := options.Name
+		if name == "" {
+			name = ca.Name
+		}
+		toList.client = c
+		return toList.NewCardContext(ctx, name, ca.Description, options.Position)
+	}
+
+	body := fields{
+		"idList":         toList.ID,
+		"idCardSource":   ca.ID,
+		"keepFromSource": keep,
+	}
+	if options.Name != "" {
+		body["name"] = options.Name
+	}
+	if options.Position != "" {
+		body["pos"] = options.Position
+	}
+	var out Card
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, "cards", body, &out); err != nil {
+		return Card{}, err
+	}
+	out.Board = toList.Board
+	out.List = toList
+	out.client = c
+	for i := range out.Labels {
+		out.Labels[i].client = c
+	}
+	return out, nil
+}
+
+func (ca *Card) Checklists() (Checklists, error) {
+	return ca.ChecklistsContext(context.Background())
+}
+
+func (ca *Card) ChecklistsContext(ctx context.Context) (Checklists, error) {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "checklists")
+	var out Checklists
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Card = *ca
+		out[i].Board = ca.Board
+		out[i].client = c
+		for j := range out[i].CheckItems {
+			// Properly set the Checklist for every CheckItem.
+			out[i].CheckItems[j].Checklist = out[i]
+			out[i].CheckItems[j].client = c
+		}
+	}
+	return out, nil
+}
+
+// NewChecklist adds a checklist to the card. If idChecklistSource isn't
+// empty, the items of that checklist are copied to the new one.
+func (ca *Card) NewChecklist(name, idChecklistSource string) (Checklist, error) {
+	return ca.NewChecklistContext(context.Background(), name, idChecklistSource)
+}
+
+func (ca *Card) NewChecklistContext(ctx context.Context, name, idChecklistSource string) (Checklist, error) {
+	c := ca.client
+	body := fields{"idCard": ca.ID, "name": name}
+	if idChecklistSource != "" {
+		body["idChecklistSource"] = idChecklistSource
+	}
+	var out Checklist
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, "checklists", body, &out); err != nil {
+		return Checklist{}, err
+	}
+	ca.IDChecklists = append(ca.IDChecklists, out.ID)
+	out.Card = *ca
+	out.Board = ca.Board
+	out.client = c
+	for i := range out.CheckItems {
+		out.CheckItems[i].Checklist = out
+		out.CheckItems[i].client = c
+	}
+	return out, nil
+}
+
+func (ca *Card) AddLabel(labelID string) error {
+	return ca.AddLabelContext(context.Background(), labelID)
+}
+
+func (ca *Card) AddLabelContext(ctx context.Context, labelID string) error {
+	return ca.addLabel(ctx, Label{ID: labelID})
+}
+
+// AddLabelByName adds the label with the given name on the card's board.
+func (ca *Card) AddLabelByName(name string) error {
+	return ca.AddLabelByNameContext(context.Background(), name)
+}
+
+func (ca *Card) AddLabelByNameContext(ctx context.Context, name string) error {
+	label, err := ca.findBoardLabel(ctx, Labels.Find, name)
+	if err != nil {
+		return err
+	}
+	return ca.addLabel(ctx, label)
+}
+
+// AddLabelByColor adds the first label with the given color on the card's board.
+func (ca *Card) AddLabelByColor(color string) error {
+	return ca.AddLabelByColorContext(context.Background(), color)
+}
+
+func (ca *Card) AddLabelByColorContext(ctx context.Context, color string) error {
+	label, err := ca.findBoardLabel(ctx, Labels.FindColor, color)
+	if err != nil {
+		return err
+	}
+	return ca.addLabel(ctx, label)
+}
+
+func (ca *Card) addLabel(ctx context.Context, label Label) error {
+	// Don't add labels the card already has.
+	for _, id := range ca.IDLabels {
+		if id == label.ID {
+			return nil
+		}
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "idLabels")
+	if err := c.doMethod(ctx, http.MethodPost, apiurl, fields{"value": label.ID}); err != nil {
+		return err
+	}
+	ca.IDLabels = append(ca.IDLabels, label.ID)
+	if label.Name != "" || label.Color != "" {
+		ca.Labels = append(ca.Labels, label)
+	}
+	return nil
+}
+
+func (ca *Card) RemoveLabel(labelID string) error {
+	return ca.RemoveLabelContext(context.Background(), labelID)
+}
+
+func (ca *Card) RemoveLabelContext(ctx context.Context, labelID string) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "idLabels", labelID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range ca.IDLabels {
+		if id != labelID {
+			ids = append(ids, id)
+		}
+	}
+	var labels Labels
+	for _, l := range ca.Labels {
+		if l.ID != labelID {
+			labels = append(labels, l)
+		}
+	}
+	ca.IDLabels, ca.Labels = ids, labels
+	return nil
+}
+
+// RemoveLabelByName removes the label with the given name on the card's board.
+func (ca *Card) RemoveLabelByName(name string) error {
+	return ca.RemoveLabelByNameContext(context.Background(), name)
+}
+
+func (ca *Card) RemoveLabelByNameContext(ctx context.Context, name string) error {
+	label, err := ca.findBoardLabel(ctx, Labels.Find, name)
+	if err != nil {
+		return err
+	}
+	return ca.RemoveLabelContext(ctx, label.ID)
+}
+
+// RemoveLabelByColor removes the first label with the given color on the card's board.
+func (ca *Card) RemoveLabelByColor(color string) error {
+	return ca.RemoveLabelByColorContext(context.Background(), color)
+}
+
+func (ca *Card) RemoveLabelByColorContext(ctx context.Context, color string) error {
+	label, err := ca.findBoardLabel(ctx, Labels.FindColor, color)
+	if err != nil {
+		return err
+	}
+	return ca.RemoveLabelContext(ctx, label.ID)
+}
+
+func (ca *Card) findBoardLabel(ctx context.Context, find func(Labels, string) (*Label, error), s string) (Label, error) {
+	board := Board{ID: ca.IDBoard, client: ca.client}
+	if board.ID == "" {
+		board.ID = ca.Board.ID
+	}
+	labels, err := board.LabelsContext(ctx)
+	if err != nil {
+		return Label{}, err
+	}
+	l, err := find(labels, s)
+	return *l, err
+}
+
+func (ca *Card) Members() (Members, error) {
+	return ca.MembersContext(context.Background())
+}
+
+func (ca *Card) MembersContext(ctx context.Context) (Members, error) {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "members")
+	var out Members
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].client = c
+	}
+	return out, nil
+}
+
+func (ca *Card) AddMember(memberID string) error {
+	return ca.AddMemberContext(context.Background(), memberID)
+}
+
+func (ca *Card) AddMemberContext(ctx context.Context, memberID string) error {
+	// Don't add members the card already has.
+	for _, id := range ca.IDMembers {
+		if id == memberID {
+			return nil
+		}
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "idMembers")
+	if err := c.doMethod(ctx, http.MethodPost, apiurl, fields{"value": memberID}); err != nil {
+		return err
+	}
+	ca.IDMembers = append(ca.IDMembers, memberID)
+	return nil
+}
+
+func (ca *Card) RemoveMember(memberID string) error {
+	return ca.RemoveMemberContext(context.Background(), memberID)
+}
+
+func (ca *Card) RemoveMemberContext(ctx context.Context, memberID string) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "idMembers", memberID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	var ids []string
+	for _, id := range ca.IDMembers {
+		if id != memberID {
+			ids = append(ids, id)
+		}
+	}
+	ca.IDMembers = ids
+	return nil
+}
+
+// Trello rejects comments longer than this.
+const maxCommentLength = 16384
+
+var ErrCommentTooLong = fmt.Errorf("comment is longer than %d characters", maxCommentLength)
+
+// Comment adds a comment to the card. The text is sent as is, so Markdown in
+// it is kept exactly.
+func (ca *Card) Comment(text string) (CommentCardAction, error) {
+	return ca.CommentContext(context.Background(), text)
+}
+
+func (ca *Card) CommentContext(ctx context.Context, text string) (CommentCardAction, error) {
+	if utf8.RuneCountInString(text) > maxCommentLength {
+		return CommentCardAction{}, ErrCommentTooLong
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "actions", "comments")
+	var out Action
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, fields{"text": text}, &out); err != nil {
+		return CommentCardAction{}, err
+	}
+	return ca.commentAction(out)
+}
+
+// Comments gets all of the card's comments, newest first. Trello returns at
+// most 1000 at a time, so cards with more take several requests.
+func (ca *Card) Comments() ([]CommentCardAction, error) {
+	return ca.CommentsContext(context.Background())
+}
+
+// commentsPageSize is the most actions Trello returns for one request.
+var commentsPageSize = 1000
+
+func (ca *Card) CommentsContext(ctx context.Context) ([]CommentCardAction, error) {
+	c := ca.client
+	query := url.Values{"filter": {ActionCommentCard}, "limit": {strconv.Itoa(commentsPageSize)}}
+	var out []CommentCardAction
+	for {
+		apiurl := apiPath("cards", ca.ID, "actions") + "?" + query.Encode()
+		var actions []Action
+		if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &actions); err != nil {
+			return nil, err
+		}
+		for i := range actions {
+			comment, err := ca.commentAction(actions[i])
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, comment)
+		}
+		// A short page is the last one; otherwise carry on with the older
+		// comments.
+		if len(actions) < commentsPageSize {
+			return out, nil
+		}
+		query.Set("before", actions[len(actions)-1].ID)
+	}
+}
+
+func (ca *Card) commentAction(a Action) (CommentCardAction, error) {
+	typed, err := a.Typed()
+	if err != nil {
+		return CommentCardAction{}, err
+	}
+	comment, ok := typed.(CommentCardAction)
+	if !ok {
+		return CommentCardAction{}, fmt.Errorf("expected a %s action, got %q", ActionCommentCard, a.Type)
+	}
+	comment.Card = *ca
+	comment.MemberCreator.client = ca.client
+	return comment, nil
+}
+
+// EditComment replaces the text of one of the card's comments. Only the
+// comment's author can edit it.
+func (ca *Card) EditComment(commentID, text string) error {
+	return ca.EditCommentContext(context.Background(), commentID, text)
+}
+
+func (ca *Card) EditCommentContext(ctx context.Context, commentID, text string) error {
+	if utf8.RuneCountInString(text) > maxCommentLength {
+		return ErrCommentTooLong
+	}
+
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "actions", commentID, "comments")
+	return c.doMethod(ctx, http.MethodPut, apiurl, fields{"text": text})
+}
+
+// DeleteComment deletes one of the card's comments. Only the comment's
+// author can delete it.
+func (ca *Card) DeleteComment(commentID string) error {
+	return ca.DeleteCommentContext(context.Background(), commentID)
+}
+
+func (ca *Card) DeleteCommentContext(ctx context.Context, commentID string) error {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "actions", commentID, "comments")
+	return c.doMethod(ctx, http.MethodDelete, apiurl, nil)
+}
+
+func (ca *Card) Attachments() (Attachments, error) {
+	return ca.AttachmentsContext(context.Background())
+}
+
+func (ca *Card) AttachmentsContext(ctx context.Context) (Attachments, error) {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "attachments")
+	var out Attachments
+	if err := c.doMethodAndParseBody(ctx, http.MethodGet, apiurl, nil, &out); err != nil {
+		return nil, err
+	}
+	for i := range out {
+		out[i].Card = *ca
+		out[i].client = c
+	}
+	return out, nil
+}
+
+// AttachURL attaches a link to the card. name may be empty, in which case
+// Trello uses the URL.
+func (ca *Card) AttachURL(link, name string) (Attachment, error) {
+	return ca.AttachURLContext(context.Background(), link, name)
+}
+
+func (ca *Card) AttachURLContext(ctx context.Context, link, name string) (Attachment, error) {
+	body := fields{"url": link}
+	if name != "" {
+		body["name"] = name
+	}
+	return ca.attach(ctx, body)
+}
+
+// AttachFile uploads the file at path to the card. The file is streamed, so
+// it's never read into memory all at once.
+func (ca *Card) AttachFile(path string) (Attachment, error) {
+	return ca.AttachFileContext(context.Background(), path)
+}
+
+func (ca *Card) AttachFileContext(ctx context.Context, path string) (Attachment, error) {
+	// Fail early, instead of when the request body is read.
+	f, err := os.Open(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+	f.Close()
+
+	return ca.attach(ctx, newFileUpload(path, map[string]string{"name": filepath.Base(path)}))
+}
+
+func (ca *Card) attach(ctx context.Context, body interface{}) (Attachment, error) {
+	c := ca.client
+	apiurl := apiPath("cards", ca.ID, "attachments")
+	var out Attachment
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, body, &out); err != nil {
+		return Attachment{}, err
+	}
+	out.Card = *ca
+	out.client = c
+	return out, nil
+}
+
+// Created is the time the card was created, which Trello keeps in its ID.
+func (ca Card) Created() time.Time {
+	if len(ca.ID) < 8 {
+		return time.Time{}
+	}
+	secs, err := strconv.ParseInt(ca.ID[:8], 16, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(secs, 0).UTC()
+}
+
+func (cs Cards) Find(name string) (*Card, error) {
+	for i := range cs {
+		if cs[i].Name == name {
+			return &cs[i], nil
+		}
+	}
+	return &Card{}, NotFoundError{Type: "Card", Identifier: name}
+}
+
+func (cl *Checklist) Rename(name string) error {
+	return cl.RenameContext(context.Background(), name)
+}
+
+func (cl *Checklist) RenameContext(ctx context.Context, name string) error {
+	if cl.Name == name {
+		return nil
+	}
+
+	c := cl.client
+	apiurl := apiPath("checklists", cl.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name}); err != nil {
+		return err
+	}
+	cl.Name = name
+	return nil
+}
+
+func (cl *Checklist) Delete() error {
+	return cl.DeleteContext(context.Background())
+}
+
+func (cl *Checklist) DeleteContext(ctx context.Context) error {
+	c := cl.client
+	apiurl := apiPath("checklists", cl.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*cl = Checklist{}
+	return nil
+}
+
+// AddItem adds an item to the checklist at position: "top", "bottom" or a
+// number. An empty position adds it at the bottom.
+func (cl *Checklist) AddItem(name, position string, checked bool) (CheckItem, error) {
+	return cl.AddItemContext(context.Background(), name, position, checked)
+}
+
+func (cl *Checklist) AddItemContext(ctx context.Context, name, position string, checked bool) (CheckItem, error) {
+	c := cl.client
+	body := fields{"name": name, "checked": checked}
+	if position != "" {
+		body["pos"] = position
+	}
+	apiurl := apiPath("checklists", cl.ID, "checkItems")
+	var out CheckItem
+	if err := c.doMethodAndParseBody(ctx, http.MethodPost, apiurl, body, &out); err != nil {
+		return CheckItem{}, err
+	}
+	out.Checklist = *cl
+	out.client = c
+	cl.CheckItems = append(cl.CheckItems, out)
+	return out, nil
+}
+
+func (ci *CheckItem) Complete() error {
+	return ci.CompleteContext(context.Background())
+}
+
+func (ci *CheckItem) CompleteContext(ctx context.Context) error {
+	c := ci.client
+	apiurl := apiPath("cards", ci.Checklist.IDCard, "checkItem", ci.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"state": StateComplete}); err != nil {
+		return err
+	}
+	ci.State = StateComplete
+	return nil
+}
+
+func (ci *CheckItem) Incomplete() error {
+	return ci.IncompleteContext(context.Background())
+}
+
+func (ci *CheckItem) IncompleteContext(ctx context.Context) error {
+	c := ci.client
+	apiurl := apiPath("cards", ci.Checklist.IDCard, "checkItem", ci.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"state": StateIncomplete}); err != nil {
+		return err
+	}
+	ci.State = StateIncomplete
+	return nil
+}
+
+// Done reports whether the item is complete.
+func (ci CheckItem) Done() bool {
+	return ci.State == StateComplete
+}
+
+// Progress counts the complete items of the checklist, as it was loaded.
+func (cl Checklist) Progress() Progress {
+	return cl.CheckItems.Progress()
+}
+
+func (cis CheckItems) Progress() Progress {
+	p := Progress{Total: len(cis)}
+	for i := range cis {
+		if cis[i].Done() {
+			p.Completed++
+		}
+	}
+	return p
+}
+
+// Progress counts the complete items of all the checklists.
+func (cls Checklists) Progress() Progress {
+	var p Progress
+	for i := range cls {
+		cp := cls[i].Progress()
+		p.Completed += cp.Completed
+		p.Total += cp.Total
+	}
+	return p
+}
+
+// Progress counts the complete items of all the card's checklists, from the
+// card's badges. Use Checklists for the progress of each checklist.
+func (ca Card) Progress() Progress {
+	return Progress{Completed: ca.Badges.CheckItemsChecked, Total: ca.Badges.CheckItems}
+}
+
+// Percent is the percentage of complete items, 0 if there are no items.
+func (p Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 0
+	}
+	return 100 * float64(p.Completed) / float64(p.Total)
+}
+
+func (cis CheckItems) Find(name string) (*CheckItem, error) {
+	for i := range cis {
+		if cis[i].Name == name {
+			return &cis[i], nil
+		}
+	}
+	return &CheckItem{}, NotFoundError{Type: "CheckItem", Identifier: name}
+}
+
+func (ci *CheckItem) Rename(name string) error {
+	return ci.RenameContext(context.Background(), name)
+}
+
+func (ci *CheckItem) RenameContext(ctx context.Context, name string) error {
+	c := ci.client
+	apiurl := apiPath("cards", ci.Checklist.IDCard, "checkItem", ci.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name}); err != nil {
+		return err
+	}
+	ci.Name = name
+	return nil
+}
+
+func (ci *CheckItem) Delete() error {
+	return ci.DeleteContext(context.Background())
+}
+
+func (ci *CheckItem) DeleteContext(ctx context.Context) error {
+	c := ci.client
+	apiurl := apiPath("checklists", ci.checklistID(), "checkItems", ci.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*ci = CheckItem{}
+	return nil
+}
+
+// SetPosition moves the item on its checklist, to "top", "bottom" or a
+// number.
+func (ci *CheckItem) SetPosition(position string) error {
+	return ci.SetPositionContext(context.Background(), position)
+}
+
+func (ci *CheckItem) SetPositionContext(ctx context.Context, position string) error {
+	c := ci.client
+	apiurl := apiPath("cards", ci.Checklist.IDCard, "checkItem", ci.ID)
+	var out CheckItem
+	if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiurl, fields{"pos": position}, &out); err != nil {
+		return err
+	}
+	ci.Pos = out.Pos
+	return nil
+}
+
+// MoveToChecklist moves the item to another checklist on the same card, at
+// position: "top", "bottom" or a number. An empty position leaves it to
+// Trello.
+func (ci *CheckItem) MoveToChecklist(checklist Checklist, position string) error {
+	return ci.MoveToChecklistContext(context.Background(), checklist, position)
+}
+
+func (ci *CheckItem) MoveToChecklistContext(ctx context.Context, checklist Checklist, position string) error {
+	c := ci.client
+	body := fields{"idChecklist": checklist.ID}
+	if position != "" {
+		body["pos"] = position
+	}
+	apiurl := apiPath("cards", ci.Checklist.IDCard, "checkItem", ci.ID)
+	var out CheckItem
+	if err := c.doMethodAndParseBody(ctx, http.MethodPut, apiurl, body, &out); err != nil {
+		return err
+	}
+	ci.IDChecklist = checklist.ID
+	ci.Checklist = checklist
+	ci.Pos = out.Pos
+	return nil
+}
+
+// checklistID is the ID of the item's checklist, if it's known.
+func (ci *CheckItem) checklistID() string {
+	if ci.IDChecklist != "" {
+		return ci.IDChecklist
+	}
+	return ci.Checklist.ID
+}
+
+func (w *Webhook) Activate() error {
+	return w.ActivateContext(context.Background())
+}
+
+func (w *Webhook) ActivateContext(ctx context.Context) error {
+	// Don't activate active webhooks.
+	if w.Active {
+		return nil
+	}
+
+	c := w.client
+	apiurl := apiPath("webhooks", w.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"active": true}); err != nil {
+		return err
+	}
+	w.Active = true
+	return nil
+}
+
+func (w *Webhook) Deactivate() error {
+	return w.DeactivateContext(context.Background())
+}
+
+func (w *Webhook) DeactivateContext(ctx context.Context) error {
+	// Don't deactivate inactive webhooks.
+	if !w.Active {
+		return nil
+	}
+
+	c := w.client
+	apiurl := apiPath("webhooks", w.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"active": false}); err != nil {
+		return err
+	}
+	w.Active = false
+	return nil
+}
+
+func (w *Webhook) Delete() error {
+	return w.DeleteContext(context.Background())
+}
+
+func (w *Webhook) DeleteContext(ctx context.Context) error {
+	c := w.client
+	apiurl := apiPath("webhooks", w.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*w = Webhook{}
+	return nil
+}
+
+func (ws Webhooks) Find(modelID string) (*Webhook, error) {
+	for i := range ws {
+		if ws[i].IDModel == modelID {
 			return &ws[i], nil
 		}
 	}
 	return &Webhook{}, NotFoundError{Type: "Webhook", Identifier: modelID}
 }
 
-func (c *Client) doMethod(method, apiurl string) error {
-	reqURL := joinPath(c.BaseURL.String(), apiurl)
-	req, err := http.NewRequest(method, reqURL, nil)
-	if err != nil {
+func (l *Label) Update(name, color string) error {
+	return l.UpdateContext(context.Background(), name, color)
+}
+
+func (l *Label) UpdateContext(ctx context.Context, name, color string) error {
+	// Don't update labels that wouldn't change.
+	if l.Name == name && l.Color == color {
+		return nil
+	}
+
+	c := l.client
+	apiurl := apiPath("labels", l.ID)
+	if err := c.doMethod(ctx, http.MethodPut, apiurl, fields{"name": name, "color": color}); err != nil {
+		return err
+	}
+	l.Name, l.Color = name, color
+	return nil
+}
+
+func (l *Label) Delete() error {
+	return l.DeleteContext(context.Background())
+}
+
+func (l *Label) DeleteContext(ctx context.Context) error {
+	c := l.client
+	apiurl := apiPath("labels", l.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
+		return err
+	}
+	*l = Label{}
+	return nil
+}
+
+func (ls Labels) Find(name string) (*Label, error) {
+	for i := range ls {
+		if ls[i].Name == name {
+			return &ls[i], nil
+		}
+	}
+	return &Label{}, NotFoundError{Type: "Label", Identifier: name}
+}
+
+func (ls Labels) FindColor(color string) (*Label, error) {
+	for i := range ls {
+		if ls[i].Color == color {
+			return &ls[i], nil
+		}
+	}
+	return &Label{}, NotFoundError{Type: "Label", Identifier: color}
+}
+
+func (ms Members) Find(username string) (*Member, error) {
+	for i := range ms {
+		if ms[i].Username == username {
+			return &ms[i], nil
+		}
+	}
+	return &Member{}, NotFoundError{Type: "Member", Identifier: username}
+}
+
+func (a *Attachment) Delete() error {
+	return a.DeleteContext(context.Background())
+}
+
+func (a *Attachment) DeleteContext(ctx context.Context) error {
+	c := a.client
+	apiurl := apiPath("cards", a.Card.ID, "attachments", a.ID)
+	if err := c.doMethod(ctx, http.MethodDelete, apiurl, nil); err != nil {
 		return err
 	}
+	*a = Attachment{}
+	return nil
+}
+
+// Download writes the attachment's content to w as it's received, and
+// returns the number of bytes written. The credentials are only sent for
+// uploaded files, never to the hosts of attached links.
+func (a *Attachment) Download(w io.Writer) (int64, error) {
+	return a.DownloadContext(context.Background(), w)
+}
+
+func (a *Attachment) DownloadContext(ctx context.Context, w io.Writer) (int64, error) {
+	c := a.client
+	u, err := url.Parse(a.URL)
+	if err != nil {
+		return 0, err
+	}
+	auth := authNone
+	if a.IsUpload {
+		auth = authHeader
+	}
+
+	resp, err := c.doURL(ctx, http.MethodGet, a.URL, u.RequestURI(), auth, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	return io.Copy(w, resp.Body)
+}
+
+func (as Attachments) Find(name string) (*Attachment, error) {
+	for i := range as {
+		if as[i].Name == name {
+			return &as[i], nil
+		}
+	}
+	return &Attachment{}, NotFoundError{Type: "Attachment", Identifier: name}
+}
+
+// fields are the parameters of a request, sent as a JSON body.
+type fields map[string]interface{}
+
+// requestBody is the body of a request. It's opened again for every attempt
+// of a request. Bodies opened as a *bytes.Reader are sent with their length,
+// and others with chunked encoding.
+type requestBody interface {
+	contentType() string
+	open() (io.Reader, error)
+}
+
+type jsonBody []byte
+
+func (j jsonBody) contentType() string {
+	return "application/json"
+}
+
+func (j jsonBody) open() (io.Reader, error) {
+	return bytes.NewReader(j), nil
+}
+
+// fileUpload sends a file as a multipart/form-data body, streaming it from
+// disk instead of reading all of it into memory.
+type fileUpload struct {
+	path     string
+	fields   map[string]string
+	boundary string
+}
+
+func newFileUpload(path string, fields map[string]string) fileUpload {
+	return fileUpload{path: path, fields: fields, boundary: multipart.NewWriter(nil).Boundary()}
+}
+
+func (f fileUpload) contentType() string {
+	return "multipart/form-data; boundary=" + f.boundary
+}
+
+func (f fileUpload) open() (io.Reader, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	mw.SetBoundary(f.boundary)
+	go func() {
+		defer file.Close()
+		var err error
+		for k, v := range f.fields {
+			if err = mw.WriteField(k, v); err != nil {
+				break
+			}
+		}
+		if err == nil {
+			var part io.Writer
+			if part, err = mw.CreateFormFile("file", filepath.Base(f.path)); err == nil {
+				_, err = io.Copy(part, file)
+			}
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
 
-	resp, err := c.client.Do(req)
+// body is sent as JSON if it isn't nil, unless it's a requestBody.
+func (c *Client) doMethod(ctx context.Context, method, apiurl string, body interface{}) error {
+	resp, err := c.do(ctx, method, apiurl, body)
 	if err != nil {
 		return err
 	}
 	resp.Body.Close() // Not deferred because we ignore the body.
-	if resp.StatusCode >= 400 {
-		return HTTPRequestError{StatusCode: resp.StatusCode}
-	}
 	return nil
 }
 
 // t must be a pointer.
-func (c *Client) doMethodAndParseBody(method, apiurl string, t interface{}) error {
-	reqURL := joinPath(c.BaseURL.String(), apiurl)
-	req, err := http.NewRequest(method, reqURL, nil)
+func (c *Client) doMethodAndParseBody(ctx context.Context, method, apiurl string, body, t interface{}) error {
+	resp, err := c.do(ctx, method, apiurl, body)
 	if err != nil {
 		return err
 	}
+	defer resp.Body.Close()
 
-	resp, err := c.client.Do(req)
+	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return HTTPRequestError{StatusCode: resp.StatusCode}
+
+	return json.Unmarshal(respBody, t)
+}
+
+// auth is how a request sends the credentials.
+type auth int
+
+const (
+	// authDefault sends them in the query, or in the Authorization header if
+	// c.AuthHeader is set.
+	authDefault auth = iota
+	authHeader
+	authNone
+)
+
+// do sends a request to apiurl, a path relative to c.BaseURL. See doURL.
+func (c *Client) do(ctx context.Context, method, apiurl string, body interface{}) (*http.Response, error) {
+	return c.doURL(ctx, method, joinPath(c.BaseURL.String(), apiurl), apiurl, authDefault, body)
+}
+
+// doURL sends the request to rawurl, waiting on c.Limiter and retrying as
+// allowed by c.Retry, and returns the first successful response. path is the
+// part of rawurl used in errors. The caller must close the response body.
+func (c *Client) doURL(ctx context.Context, method, rawurl, path string, auth auth, body interface{}) (*http.Response, error) {
+	var reqBody requestBody
+	switch b := body.(type) {
+	case nil:
+	case requestBody:
+		reqBody = b
+	default:
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = jsonBody(payload)
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.Limiter != nil {
+			if err := c.Limiter.Wait(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := c.newRequest(ctx, method, rawurl, auth, reqBody)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.client.Do(req)
+		if err == nil && resp.StatusCode < 400 {
+			return resp, nil
+		}
+		if urlErr, ok := err.(*url.Error); ok {
+			urlErr.URL = c.redact(urlErr.URL)
+		}
+		if err == nil {
+			err = c.newHTTPRequestError(method, path, resp)
+			resp.Body.Close()
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait, ok := c.Retry.backoff(method, attempt, resp, err)
+		if !ok {
+			return nil, err
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return nil, err
+		}
 	}
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
+// newRequest is the only place requests are built. It adds the credentials as
+// auth says.
+func (c *Client) newRequest(ctx context.Context, method, rawurl string, auth auth, body requestBody) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		var err error
+		if reader, err = body.open(); err != nil {
+			return nil, err
+		}
+	}
+	// http.NewRequest sets ContentLength and GetBody for a *bytes.Reader.
+	req, err := http.NewRequestWithContext(ctx, method, rawurl, reader)
 	if err != nil {
-		return err
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", body.contentType())
 	}
 
-	return json.Unmarshal(body, t)
+	switch {
+	case auth == authNone:
+		return req, nil
+	case auth == authHeader || c.AuthHeader:
+		req.Header.Set("Authorization", fmt.Sprintf(`OAuth oauth_consumer_key="%s", oauth_token="%s"`, c.APIKey, c.Token))
+		return req, nil
+	}
+	query := req.URL.Query()
+	query.Set("key", c.APIKey)
+	query.Set("token", c.Token)
+	req.URL.RawQuery = query.Encode()
+	return req, nil
+}
+
+// timeValue is t as Trello's ISO 8601 timestamps, in UTC with milliseconds,
+// or nil to clear the value if t is zero.
+func timeValue(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format("2006-01-02T15:04:05.000Z07:00")
+}
+
+// apiPath joins the segments into a path, escaping each of them.
+func apiPath(segments ...string) string {
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
 }
 
 type NotFoundError struct {
@@ -479,10 +2201,123 @@ func (n NotFoundError) Error() string {
 
 type HTTPRequestError struct {
 	StatusCode int
+	Method     string
+	Path       string // Path and query of the request, with credentials redacted.
+	Message    string // Trello's error message, if there was one.
+	Body       string
+	RequestID  string
 }
 
 func (h HTTPRequestError) Error() string {
-	return fmt.Sprintf("HTTP Request error with status: %d", h.StatusCode)
+	out := fmt.Sprintf("HTTP Request error with status: %d", h.StatusCode)
+	if h.Method != "" {
+		out += fmt.Sprintf(" (%s %s)", h.Method, h.Path)
+	}
+	if h.Message != "" {
+		out += ": " + h.Message
+	}
+	return out
+}
+
+// Error bodies longer than this are truncated.
+const maxErrorBodySize = 64 << 10
+
+// Error messages longer than this are truncated.
+const maxErrorMessageSize = 200
+
+func (c *Client) newHTTPRequestError(method, apiurl string, resp *http.Response) HTTPRequestError {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	out := HTTPRequestError{
+		StatusCode: resp.StatusCode,
+		Method:     method,
+		Path:       c.redact(apiurl),
+		Body:       string(body),
+		RequestID:  resp.Header.Get("X-Trello-Request-Id"),
+	}
+	if out.RequestID == "" {
+		out.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	out.Message = truncate(errorMessage(resp.Header.Get("Content-Type"), body), maxErrorMessageSize)
+	return out
+}
+
+// errorMessage returns the message in an error body. Trello sends most errors
+// as a line of plain text, but some as JSON. Anything else, like the HTML
+// page of a proxy, is only kept in the Body.
+func errorMessage(contentType string, body []byte) string {
+	var jsonErr struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &jsonErr) == nil {
+		if jsonErr.Message != "" {
+			return jsonErr.Message
+		}
+		return jsonErr.Error
+	}
+
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(contentType, "text/html") || strings.HasPrefix(text, "<") ||
+		strings.ContainsAny(text, "\r\n") {
+		return ""
+	}
+	return text
+}
+
+// truncate shortens s to at most n bytes, without splitting a character.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	s = s[:n-len("...")]
+	for len(s) > 0 && !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s + "..."
+}
+
+// redact removes the API key and token from s.
+func (c *Client) redact(s string) string {
+	for _, secret := range []string{c.APIKey, c.Token} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "REDACTED")
+		}
+	}
+	return s
+}
+
+// IsNotFound reports whether err is a NotFoundError, or an HTTPRequestError
+// with status 404.
+func IsNotFound(err error) bool {
+	var notFound NotFoundError
+	if errors.As(err, &notFound) {
+		return true
+	}
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsUnauthorized reports whether err is an HTTPRequestError with status 401
+// or 403, which Trello uses for invalid credentials and missing permissions.
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is an HTTPRequestError with status 429.
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, statuses ...int) bool {
+	var httpErr HTTPRequestError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	for _, status := range statuses {
+		if httpErr.StatusCode == status {
+			return true
+		}
+	}
+	return false
 }
 
 func joinPath(host, path string) string {
@@ -517,6 +2352,33 @@ func (ci CheckItem) String() string {
 	)
 }
 
+func (s CheckItemState) String() string {
+	if s == StateComplete {
+		return "complete"
+	}
+	return "incomplete"
+}
+
+func (s CheckItemState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+func (s *CheckItemState) UnmarshalJSON(data []byte) error {
+	var state string
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+	switch state {
+	case "complete":
+		*s = StateComplete
+	case "incomplete", "":
+		*s = StateIncomplete
+	default:
+		return fmt.Errorf("unknown check item state %q", state)
+	}
+	return nil
+}
+
 func (cis CheckItems) String() string {
 	out := ""
 	for _, ci := range cis {
@@ -1,12 +1,21 @@
 package trel
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func setupClientMuxServer() (*Client, *http.ServeMux, *httptest.Server) {
@@ -14,6 +23,10 @@ func setupClientMuxServer() (*Client, *http.ServeMux, *httptest.Server) {
 	server := httptest.NewServer(mux)
 	client := New(server.Client(), "", "")
 	client.BaseURL, _ = url.Parse(server.URL)
+	// Every test shares the same empty key and token, so don't limit them.
+	client.Limiter = nil
+	// Tests that retry set their own policy, so errors come back at once.
+	client.Retry = RetryPolicy{}
 	return client, mux, server
 }
 
@@ -120,7 +133,7 @@ func TestClient_Checklist(t *testing.T) {
 			ID:          "4567",
 			Name:        "Test CheckItem",
 			IDChecklist: "1234",
-			State:       "incomplete",
+			State:       StateIncomplete,
 			client:      client,
 			//Checklist: set this later,
 		}},
@@ -534,11 +547,11 @@ func TestCard_Move(t *testing.T) {
 	}{
 		{ListID: list2.ID,
 			Card:    Card{IDList: list1.ID, List: list1, client: client},
-			EndCard: Card{IDList: list2.ID, List: list2, client: client},
+			EndCard: Card{IDList: list2.ID, List: List{ID: list2.ID, client: client}, client: client},
 			Err:     nil},
 		{ListID: list1.ID,
 			Card:    Card{IDList: list2.ID, List: list2, client: client},
-			EndCard: Card{IDList: list1.ID, List: list1, client: client},
+			EndCard: Card{IDList: list1.ID, List: List{ID: list1.ID, client: client}, client: client},
 			Err:     nil},
 	}
 
@@ -558,6 +571,45 @@ func TestCard_Move(t *testing.T) {
 	}
 }
 
+func TestCard_MoveOtherBoard(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "c", "idList": "l2", "idBoard": "b2", "pos": 1024}`)
+	})
+	mux.HandleFunc("/lists/l2/cards", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "c", "idList": "l2", "idBoard": "b2"}]`)
+	})
+
+	card := Card{ID: "c", IDList: "l1", IDBoard: "b1", List: List{ID: "l1"}, Board: Board{ID: "b1", Name: "Board 1"},
+		IDLabels: []string{"lb1"}, client: client}
+	if err := card.Move("l2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]interface{}{"idList": "l2"}; !reflect.DeepEqual(want, body) {
+		t.Errorf("Expected %#v, got %#v\n", want, body)
+	}
+	board := Board{ID: "b2", client: client}
+	compare := Card{ID: "c", IDList: "l2", IDBoard: "b2", Pos: 1024,
+		List: List{ID: "l2", IDBoard: "b2", Board: board, client: client}, Board: board, client: client}
+	if !reflect.DeepEqual(compare, card) {
+		t.Errorf("Expected %#v, got %#v\n", compare, card)
+	}
+
+	// The new list can be used right away.
+	cards, err := card.List.Cards()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cards) != 1 || cards[0].Board.ID != "b2" {
+		t.Errorf("Expected the card on board b2, got %#v\n", cards)
+	}
+}
+
 func TestCard_Rename(t *testing.T) {
 	client, mux, server := setupClientMuxServer()
 	defer server.Close()
@@ -608,7 +660,7 @@ func TestCard_Checklists(t *testing.T) {
 		{Checklists: Checklists{{ID: "1234", Name: "Checklist 1", Card: card, client: client, CheckItems: nil}},
 			Body: `[{"id": "1234", "name": "Checklist 1"}]`},
 		{Checklists: Checklists{{ID: "1234", Name: "Checklist 1", Card: card, client: client, CheckItems: CheckItems{
-			{ID: "2345", Name: "CheckItem 1", State: "incomplete", IDChecklist: "1234", client: client}}}},
+			{ID: "2345", Name: "CheckItem 1", State: StateIncomplete, IDChecklist: "1234", client: client}}}},
 			Body: `[{"id": "1234", "name": "Checklist 1", "checkItems": [
 				{"idChecklist": "1234", "state": "incomplete", "id": "2345", "name": "CheckItem 1"}]}]`},
 	}
@@ -692,10 +744,10 @@ func TestCheckItem_Complete(t *testing.T) {
 		CheckItem    CheckItem
 		EndCheckItem CheckItem
 	}{
-		{CheckItem: CheckItem{State: "incomplete", client: client},
-			EndCheckItem: CheckItem{State: "complete", client: client}},
-		{CheckItem: CheckItem{State: "complete", client: client},
-			EndCheckItem: CheckItem{State: "complete", client: client}},
+		{CheckItem: CheckItem{State: StateIncomplete, client: client},
+			EndCheckItem: CheckItem{State: StateComplete, client: client}},
+		{CheckItem: CheckItem{State: StateComplete, client: client},
+			EndCheckItem: CheckItem{State: StateComplete, client: client}},
 	}
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -722,10 +774,10 @@ func TestCheckItem_Incomplete(t *testing.T) {
 		CheckItem    CheckItem
 		EndCheckItem CheckItem
 	}{
-		{CheckItem: CheckItem{State: "complete", client: client},
-			EndCheckItem: CheckItem{State: "incomplete", client: client}},
-		{CheckItem: CheckItem{State: "incomplete", client: client},
-			EndCheckItem: CheckItem{State: "incomplete", client: client}},
+		{CheckItem: CheckItem{State: StateComplete, client: client},
+			EndCheckItem: CheckItem{State: StateIncomplete, client: client}},
+		{CheckItem: CheckItem{State: StateIncomplete, client: client},
+			EndCheckItem: CheckItem{State: StateIncomplete, client: client}},
 	}
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
@@ -948,3 +1000,2271 @@ func TestWebhooks_Find(t *testing.T) {
 		}
 	}
 }
+
+func TestClient_BoardsContext(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"name": "Test", "id": "1234"}]`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	boards, err := client.BoardsContext(ctx, "Username")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %q, got %q\n", context.Canceled, err)
+	}
+	if boards != nil {
+		t.Errorf("Expected nil, got %#v\n", boards)
+	}
+}
+
+func TestCard_MoveContext(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	done := make(chan struct{})
+	defer close(done)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	card := Card{IDList: "2345", client: client}
+	err := card.MoveContext(ctx, "3456")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %q, got %q\n", context.DeadlineExceeded, err)
+	}
+
+	endCard := Card{IDList: "2345", client: client}
+	if !reflect.DeepEqual(endCard, card) {
+		t.Errorf("Expected %#v, got %#v\n", endCard, card)
+	}
+}
+
+func TestHTTPRequestError(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.APIKey, client.Token = "secretkey", "secrettoken"
+
+	cases := []struct {
+		Status int
+		Body   string
+		Err    HTTPRequestError
+	}{
+		{Status: http.StatusBadRequest, Body: "invalid id\n",
+			Err: HTTPRequestError{StatusCode: 400, Method: http.MethodGet, Message: "invalid id", Body: "invalid id\n"}},
+		{Status: http.StatusUnauthorized, Body: `{"message": "unauthorized permission requested", "error": "UNAUTHORIZED"}`,
+			Err: HTTPRequestError{StatusCode: 401, Method: http.MethodGet, Message: "unauthorized permission requested",
+				Body: `{"message": "unauthorized permission requested", "error": "UNAUTHORIZED"}`}},
+		{Status: http.StatusNotFound, Body: "",
+			Err: HTTPRequestError{StatusCode: 404, Method: http.MethodGet}},
+		{Status: http.StatusBadGateway, Body: "<html>\n<head><title>502 Bad Gateway</title></head>\n</html>\n",
+			Err: HTTPRequestError{StatusCode: 502, Method: http.MethodGet,
+				Body: "<html>\n<head><title>502 Bad Gateway</title></head>\n</html>\n"}},
+		{Status: http.StatusBadRequest, Body: "first line\nsecond line",
+			Err: HTTPRequestError{StatusCode: 400, Method: http.MethodGet, Body: "first line\nsecond line"}},
+		{Status: http.StatusBadRequest, Body: strings.Repeat("é", 150),
+			Err: HTTPRequestError{StatusCode: 400, Method: http.MethodGet,
+				Message: strings.Repeat("é", 98) + "...", Body: strings.Repeat("é", 150)}},
+	}
+
+	status, body := 0, ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Trello-Request-Id", "req-1")
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	})
+
+	for _, c := range cases {
+		status, body = c.Status, c.Body
+
+		_, err := client.Board("1234")
+		var httpErr HTTPRequestError
+		if !errors.As(err, &httpErr) {
+			t.Fatalf("Expected HTTPRequestError, got %#v\n", err)
+		}
+		if strings.Contains(httpErr.Path, "secret") || strings.Contains(err.Error(), "secret") {
+			t.Errorf("Expected credentials to be redacted, got %q\n", err)
+		}
+
+		c.Err.Path = httpErr.Path
+		c.Err.RequestID = "req-1"
+		if c.Err != httpErr {
+			t.Errorf("Expected %#v, got %#v\n", c.Err, httpErr)
+		}
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		Err      error
+		NotFound bool
+	}{
+		{Err: NotFoundError{Type: "Card", Identifier: "Card 1"}, NotFound: true},
+		{Err: fmt.Errorf("finding card: %w", NotFoundError{Type: "Card"}), NotFound: true},
+		{Err: HTTPRequestError{StatusCode: 404}, NotFound: true},
+		{Err: fmt.Errorf("getting board: %w", HTTPRequestError{StatusCode: 404}), NotFound: true},
+		{Err: HTTPRequestError{StatusCode: 400}, NotFound: false},
+		{Err: errors.New("404"), NotFound: false},
+		{Err: nil, NotFound: false},
+	}
+
+	for _, c := range cases {
+		if notFound := IsNotFound(c.Err); c.NotFound != notFound {
+			t.Errorf("Expected %t for %v, got %t\n", c.NotFound, c.Err, notFound)
+		}
+	}
+}
+
+func TestIsUnauthorized(t *testing.T) {
+	cases := []struct {
+		Err          error
+		Unauthorized bool
+	}{
+		{Err: HTTPRequestError{StatusCode: 401}, Unauthorized: true},
+		{Err: HTTPRequestError{StatusCode: 403}, Unauthorized: true},
+		{Err: HTTPRequestError{StatusCode: 404}, Unauthorized: false},
+		{Err: NotFoundError{}, Unauthorized: false},
+	}
+
+	for _, c := range cases {
+		if unauthorized := IsUnauthorized(c.Err); c.Unauthorized != unauthorized {
+			t.Errorf("Expected %t for %v, got %t\n", c.Unauthorized, c.Err, unauthorized)
+		}
+	}
+}
+
+func TestIsRateLimited(t *testing.T) {
+	cases := []struct {
+		Err         error
+		RateLimited bool
+	}{
+		{Err: HTTPRequestError{StatusCode: 429}, RateLimited: true},
+		{Err: fmt.Errorf("syncing: %w", HTTPRequestError{StatusCode: 429}), RateLimited: true},
+		{Err: HTTPRequestError{StatusCode: 500}, RateLimited: false},
+	}
+
+	for _, c := range cases {
+		if rateLimited := IsRateLimited(c.Err); c.RateLimited != rateLimited {
+			t.Errorf("Expected %t for %v, got %t\n", c.RateLimited, c.Err, rateLimited)
+		}
+	}
+}
+
+func TestClient_Credentials(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.APIKey, client.Token = "key", "token"
+
+	cases := []struct {
+		AuthHeader    bool
+		Query         url.Values
+		Authorization string
+	}{
+		{AuthHeader: false,
+			Query:         url.Values{"key": {"key"}, "token": {"token"}},
+			Authorization: ""},
+		{AuthHeader: true,
+			Query:         url.Values{},
+			Authorization: `OAuth oauth_consumer_key="key", oauth_token="token"`},
+	}
+
+	var query url.Values
+	authorization := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		query, authorization = r.URL.Query(), r.Header.Get("Authorization")
+		fmt.Fprint(w, "{}")
+	})
+
+	for _, c := range cases {
+		client.AuthHeader = c.AuthHeader
+
+		card := Card{client: client}
+		if err := card.Rename("a&b"); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.Query, query) {
+			t.Errorf("Expected %#v, got %#v\n", c.Query, query)
+		}
+		if c.Authorization != authorization {
+			t.Errorf("Expected %q, got %q\n", c.Authorization, authorization)
+		}
+	}
+}
+
+func TestClient_RedactNetworkError(t *testing.T) {
+	client, _, server := setupClientMuxServer()
+	server.Close()
+	client.APIKey, client.Token = "secretkey", "secrettoken"
+
+	_, err := client.Webhooks()
+	if err == nil {
+		t.Fatal("Expected an error from a closed server")
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("Expected credentials to be redacted, got %q\n", err)
+	}
+}
+
+func TestClient_RequestBody(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	list := List{ID: "1234", client: client}
+	longDesc := strings.Repeat("line with & and ? and # and %20\n", 1000)
+	cases := []struct {
+		Name     string
+		Desc     string
+		Position string
+		Body     map[string]interface{}
+	}{
+		{Name: "Card 1", Desc: "first card", Position: "top",
+			Body: map[string]interface{}{"idList": "1234", "name": "Card 1", "desc": "first card", "pos": "top"}},
+		{Name: "a&b=c/d?e", Desc: longDesc, Position: "",
+			Body: map[string]interface{}{"idList": "1234", "name": "a&b=c/d?e", "desc": longDesc}},
+	}
+
+	var body map[string]interface{}
+	path, contentType := "", ""
+	var contentLength, bodyLength int64
+	var transferEncoding []string
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+		contentLength, transferEncoding = r.ContentLength, r.TransferEncoding
+		raw, _ := ioutil.ReadAll(r.Body)
+		bodyLength = int64(len(raw))
+		body = nil
+		if err := json.Unmarshal(raw, &body); err != nil {
+			t.Error(err)
+		}
+		fmt.Fprint(w, "{}")
+	})
+
+	for _, c := range cases {
+		if _, err := list.NewCard(c.Name, c.Desc, c.Position); err != nil {
+			t.Fatal(err)
+		}
+
+		if path != "/cards" {
+			t.Errorf("Expected %q, got %q\n", "/cards", path)
+		}
+		if contentType != "application/json" {
+			t.Errorf("Expected %q, got %q\n", "application/json", contentType)
+		}
+		if !reflect.DeepEqual(c.Body, body) {
+			t.Errorf("Expected %#v, got %#v\n", c.Body, body)
+		}
+		// JSON bodies are sent with their length, not chunked.
+		if contentLength != bodyLength || transferEncoding != nil {
+			t.Errorf("Expected a Content-Length of %d, got %d and %v\n", bodyLength, contentLength, transferEncoding)
+		}
+	}
+}
+
+func TestClient_RequestBodyRedirect(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/cards/old", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/cards/new?"+r.URL.RawQuery, http.StatusTemporaryRedirect)
+	})
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/new", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	card := Card{ID: "old", client: client}
+	if err := card.Rename("Renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "Renamed" {
+		t.Errorf("Expected the body to be sent again after the redirect, got %#v\n", body)
+	}
+}
+
+func TestApiPath(t *testing.T) {
+	cases := []struct {
+		Segments []string
+		Path     string
+	}{
+		{Segments: []string{"cards", "1234"}, Path: "cards/1234"},
+		{Segments: []string{"members", "user/name?x", "boards"}, Path: "members/user%2Fname%3Fx/boards"},
+	}
+
+	for _, c := range cases {
+		if path := apiPath(c.Segments...); c.Path != path {
+			t.Errorf("Expected %q, got %q\n", c.Path, path)
+		}
+	}
+}
+
+// webhookServer keeps webhooks in memory and serves the webhook endpoints.
+type webhookServer struct {
+	webhooks Webhooks
+	nextID   int
+}
+
+func (s *webhookServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Description *string `json:"description"`
+		CallbackURL *string `json:"callbackURL"`
+		IDModel     *string `json:"idModel"`
+		Active      *bool   `json:"active"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	update := func(wh *Webhook) {
+		if body.Description != nil {
+			wh.Description = *body.Description
+		}
+		if body.CallbackURL != nil {
+			wh.CallbackURL = *body.CallbackURL
+		}
+		if body.IDModel != nil {
+			wh.IDModel = *body.IDModel
+		}
+		if body.Active != nil {
+			wh.Active = *body.Active
+		}
+	}
+
+	if r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/tokens/") {
+		json.NewEncoder(w).Encode(s.webhooks)
+		return
+	}
+	if r.Method == http.MethodPost && r.URL.Path == "/webhooks" {
+		s.nextID++
+		wh := Webhook{ID: fmt.Sprintf("new%d", s.nextID), Active: true}
+		update(&wh)
+		s.webhooks = append(s.webhooks, wh)
+		json.NewEncoder(w).Encode(wh)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/webhooks/")
+	for i := range s.webhooks {
+		if s.webhooks[i].ID != id {
+			continue
+		}
+		switch r.Method {
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(s.webhooks[i])
+		case http.MethodPut:
+			update(&s.webhooks[i])
+			json.NewEncoder(w).Encode(s.webhooks[i])
+		case http.MethodDelete:
+			s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+			fmt.Fprint(w, "{}")
+		}
+		return
+	}
+	http.Error(w, "model not found", http.StatusNotFound)
+}
+
+// stored returns the webhooks without a client, for comparing.
+func (s *webhookServer) stored() Webhooks {
+	out := make(Webhooks, len(s.webhooks))
+	for i, w := range s.webhooks {
+		w.client = nil
+		out[i] = w
+	}
+	return out
+}
+
+func TestClient_EnsureWebhook(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Token = "token"
+
+	const cb = "https://example.com/hook"
+	other := Webhook{ID: "o", IDModel: "other", CallbackURL: cb, Description: "Other", Active: true}
+	cases := []struct {
+		Webhooks    Webhooks
+		Webhook     Webhook
+		EndWebhooks Webhooks
+	}{
+		// Nothing exists yet, so a webhook is created.
+		{Webhooks: Webhooks{other},
+			Webhook:     Webhook{ID: "new1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			EndWebhooks: Webhooks{other, {ID: "new1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}}},
+		// A matching webhook is reused as is.
+		{Webhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}, other},
+			Webhook:     Webhook{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			EndWebhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}, other}},
+		// An inactive webhook is reactivated and its description updated.
+		{Webhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Old", Active: false}},
+			Webhook:     Webhook{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			EndWebhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}}},
+		// The webhook with the right callback is kept, and the duplicates removed.
+		{Webhooks: Webhooks{
+			{ID: "1", IDModel: "m", CallbackURL: "https://old.example.com", Description: "Desc", Active: true},
+			{ID: "2", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			{ID: "3", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: false},
+			other},
+			Webhook:     Webhook{ID: "2", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			EndWebhooks: Webhooks{{ID: "2", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}, other}},
+		// Without one for the callback, an existing webhook is pointed at it.
+		{Webhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: "https://old.example.com", Description: "Desc", Active: true}},
+			Webhook:     Webhook{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true},
+			EndWebhooks: Webhooks{{ID: "1", IDModel: "m", CallbackURL: cb, Description: "Desc", Active: true}}},
+	}
+
+	ws := &webhookServer{}
+	mux.Handle("/", ws)
+
+	for _, c := range cases {
+		ws.webhooks = append(Webhooks{}, c.Webhooks...)
+		ws.nextID = 0
+
+		webhook, err := client.EnsureWebhook("m", cb, "Desc")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c.Webhook.client = client
+		if !reflect.DeepEqual(c.Webhook, webhook) {
+			t.Errorf("Expected %#v, got %#v\n", c.Webhook, webhook)
+		}
+		if got := ws.stored(); !reflect.DeepEqual(c.EndWebhooks, got) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndWebhooks, got)
+		}
+	}
+}
+
+func TestClient_EnsureWebhookUpdateFails(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Token = "token"
+
+	const cb = "https://example.com/hook"
+	webhooks := Webhooks{
+		{ID: "1", IDModel: "m", CallbackURL: "https://old.example.com", Description: "Desc", Active: true},
+		{ID: "2", IDModel: "m", CallbackURL: "https://old.example.com", Description: "Desc", Active: true}}
+
+	ws := &webhookServer{webhooks: append(Webhooks{}, webhooks...)}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			http.Error(w, "invalid value for callbackURL", http.StatusBadRequest)
+			return
+		}
+		ws.ServeHTTP(w, r)
+	})
+
+	_, err := client.EnsureWebhook("m", cb, "Desc")
+	if code := statusCode(err); code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %v\n", http.StatusBadRequest, err)
+	}
+	// The duplicates are only deleted once the kept webhook is updated.
+	if got := ws.stored(); !reflect.DeepEqual(webhooks, got) {
+		t.Errorf("Expected %#v, got %#v\n", webhooks, got)
+	}
+}
+
+func TestBoard_Labels(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	board := Board{ID: "1234", client: client}
+	cases := []struct {
+		Labels Labels
+		Body   string
+	}{
+		{Labels: Labels{
+			{ID: "2345", Name: "Bug", Color: "red", IDBoard: "1234", Board: board, client: client},
+			{ID: "3456", Name: "", Color: "green", IDBoard: "1234", Board: board, client: client}},
+			Body: `[{"id": "2345", "name": "Bug", "color": "red", "idBoard": "1234"}, {"id": "3456", "name": "", "color": "green", "idBoard": "1234"}]`},
+	}
+
+	body := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+
+	for _, c := range cases {
+		body = c.Body
+
+		labels, err := board.Labels()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.Labels, labels) {
+			t.Errorf("Expected %#v, got %#v\n", c.Labels, labels)
+		}
+	}
+}
+
+func TestBoard_NewLabel(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	board := Board{ID: "1234", client: client}
+	compare := Label{ID: "2345", Name: "Bug", Color: "red", IDBoard: "1234", Board: board, client: client}
+
+	var body map[string]interface{}
+	mux.HandleFunc("/boards/1234/labels", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "2345", "name": "Bug", "color": "red", "idBoard": "1234"}`)
+	})
+
+	label, err := board.NewLabel("Bug", "red")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]interface{}{"name": "Bug", "color": "red"}; !reflect.DeepEqual(want, body) {
+		t.Errorf("Expected %#v, got %#v\n", want, body)
+	}
+	if !reflect.DeepEqual(compare, label) {
+		t.Errorf("Expected %#v, got %#v\n", compare, label)
+	}
+}
+
+func TestClient_CardLabels(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1234", "name": "Card", "idLabels": ["2345"], "labels": [{"id": "2345", "name": "Bug", "color": "red", "idBoard": "3456"}]}`)
+	})
+
+	compare := Card{ID: "1234", Name: "Card", IDLabels: []string{"2345"},
+		Labels: Labels{{ID: "2345", Name: "Bug", Color: "red", IDBoard: "3456", client: client}}, client: client}
+
+	card, err := client.Card("1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, card) {
+		t.Errorf("Expected %#v, got %#v\n", compare, card)
+	}
+}
+
+func TestCard_AddLabel(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	cases := []struct {
+		LabelID  string
+		Card     Card
+		EndCard  Card
+		Requests int
+	}{
+		{LabelID: "1",
+			Card:     Card{ID: "c", client: client},
+			EndCard:  Card{ID: "c", IDLabels: []string{"1"}, client: client},
+			Requests: 1},
+		{LabelID: "2",
+			Card:     Card{ID: "c", IDLabels: []string{"1"}, client: client},
+			EndCard:  Card{ID: "c", IDLabels: []string{"1", "2"}, client: client},
+			Requests: 1},
+		{LabelID: "1",
+			Card:     Card{ID: "c", IDLabels: []string{"1"}, client: client},
+			EndCard:  Card{ID: "c", IDLabels: []string{"1"}, client: client},
+			Requests: 0},
+	}
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c/idLabels", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "[]")
+	})
+
+	for _, c := range cases {
+		requests = 0
+
+		if err := c.Card.AddLabel(c.LabelID); err != nil {
+			t.Fatal(err)
+		}
+
+		if c.Requests != requests {
+			t.Errorf("Expected %d requests, got %d\n", c.Requests, requests)
+		}
+		if c.Requests > 0 && body["value"] != c.LabelID {
+			t.Errorf("Expected %q, got %q\n", c.LabelID, body["value"])
+		}
+		if !reflect.DeepEqual(c.EndCard, c.Card) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndCard, c.Card)
+		}
+	}
+}
+
+func TestCard_AddLabelByName(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/boards/b/labels", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "1", "name": "Bug", "color": "red", "idBoard": "b"}, {"id": "2", "name": "", "color": "green", "idBoard": "b"}]`)
+	})
+	mux.HandleFunc("/cards/c/idLabels", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "[]")
+	})
+
+	card := Card{ID: "c", IDBoard: "b", client: client}
+	if err := card.AddLabelByName("Bug"); err != nil {
+		t.Fatal(err)
+	}
+	if err := card.AddLabelByColor("green"); err != nil {
+		t.Fatal(err)
+	}
+	if err := card.AddLabelByName("Feature"); err != (NotFoundError{Type: "Label", Identifier: "Feature"}) {
+		t.Errorf("Expected NotFoundError, got %#v\n", err)
+	}
+
+	board := Board{ID: "b", client: client}
+	compare := Card{ID: "c", IDBoard: "b", IDLabels: []string{"1", "2"}, Labels: Labels{
+		{ID: "1", Name: "Bug", Color: "red", IDBoard: "b", Board: board, client: client},
+		{ID: "2", Name: "", Color: "green", IDBoard: "b", Board: board, client: client}},
+		client: client}
+	if !reflect.DeepEqual(compare, card) {
+		t.Errorf("Expected %#v, got %#v\n", compare, card)
+	}
+}
+
+func TestCard_RemoveLabel(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "[]")
+	})
+
+	card := Card{ID: "c", IDLabels: []string{"1", "2"},
+		Labels: Labels{{ID: "1", Name: "Bug"}, {ID: "2", Name: "Feature"}}, client: client}
+	if err := card.RemoveLabel("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "DELETE /cards/c/idLabels/1"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	compare := Card{ID: "c", IDLabels: []string{"2"}, Labels: Labels{{ID: "2", Name: "Feature"}}, client: client}
+	if !reflect.DeepEqual(compare, card) {
+		t.Errorf("Expected %#v, got %#v\n", compare, card)
+	}
+}
+
+func TestLabel_Update(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	cases := []struct {
+		Name     string
+		Color    string
+		Label    Label
+		EndLabel Label
+	}{
+		{Name: "Bug", Color: "orange",
+			Label:    Label{Name: "Bug", Color: "red", client: client},
+			EndLabel: Label{Name: "Bug", Color: "orange", client: client}},
+		{Name: "Defect", Color: "red",
+			Label:    Label{Name: "Bug", Color: "red", client: client},
+			EndLabel: Label{Name: "Defect", Color: "red", client: client}},
+	}
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+
+	for _, c := range cases {
+		if err := c.Label.Update(c.Name, c.Color); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.EndLabel, c.Label) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndLabel, c.Label)
+		}
+	}
+}
+
+func TestLabel_Delete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "{}")
+	})
+
+	label := Label{ID: "1", Name: "Bug", client: client}
+	if err := label.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(Label{}, label) {
+		t.Errorf("Expected %#v, got %#v\n", Label{}, label)
+	}
+}
+
+func TestLabels_Find(t *testing.T) {
+	label1 := Label{ID: "2345", Name: "Label 1", Color: "red"}
+	label2 := Label{ID: "3456", Name: "Label 2", Color: "green"}
+	label3 := Label{ID: "4567", Name: "", Color: "blue"}
+	cases := []struct {
+		LabelName  string
+		FoundLabel *Label
+		Labels     Labels
+		Err        error
+	}{
+		{LabelName: label1.Name,
+			FoundLabel: &label1,
+			Labels:     Labels{label1, label2, label3},
+			Err:        nil},
+		{LabelName: label2.Name,
+			FoundLabel: &label2,
+			Labels:     Labels{label1, label2, label3},
+			Err:        nil},
+		{LabelName: label1.Name,
+			FoundLabel: &Label{},
+			Labels:     Labels{label2, label3},
+			Err:        NotFoundError{Type: "Label", Identifier: label1.Name}},
+	}
+
+	for _, c := range cases {
+		label, err := c.Labels.Find(c.LabelName)
+		if c.Err != err {
+			t.Errorf("Expected %q, got %q\n", c.Err, err)
+		}
+
+		if !reflect.DeepEqual(c.FoundLabel, label) {
+			t.Errorf("Expected %#v, got %#v\n", c.FoundLabel, label)
+		}
+	}
+}
+
+func TestLabels_FindColor(t *testing.T) {
+	label1 := Label{ID: "2345", Name: "Label 1", Color: "red"}
+	label2 := Label{ID: "3456", Name: "", Color: "blue"}
+	cases := []struct {
+		Color      string
+		FoundLabel *Label
+		Labels     Labels
+		Err        error
+	}{
+		{Color: "red",
+			FoundLabel: &label1,
+			Labels:     Labels{label1, label2},
+			Err:        nil},
+		{Color: "blue",
+			FoundLabel: &label2,
+			Labels:     Labels{label1, label2},
+			Err:        nil},
+		{Color: "green",
+			FoundLabel: &Label{},
+			Labels:     Labels{label1, label2},
+			Err:        NotFoundError{Type: "Label", Identifier: "green"}},
+	}
+
+	for _, c := range cases {
+		label, err := c.Labels.FindColor(c.Color)
+		if c.Err != err {
+			t.Errorf("Expected %q, got %q\n", c.Err, err)
+		}
+
+		if !reflect.DeepEqual(c.FoundLabel, label) {
+			t.Errorf("Expected %#v, got %#v\n", c.FoundLabel, label)
+		}
+	}
+}
+
+func TestClient_Member(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{"id": "1234", "username": "alice", "fullName": "Alice A", "initials": "AA"}`)
+	})
+
+	compare := Member{ID: "1234", Username: "alice", FullName: "Alice A", Initials: "AA", client: client}
+
+	member, err := client.Member("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/members/alice" {
+		t.Errorf("Expected %q, got %q\n", "/members/alice", path)
+	}
+	if !reflect.DeepEqual(compare, member) {
+		t.Errorf("Expected %#v, got %#v\n", compare, member)
+	}
+
+	me, err := client.Me()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/members/me" {
+		t.Errorf("Expected %q, got %q\n", "/members/me", path)
+	}
+	if !reflect.DeepEqual(compare, me) {
+		t.Errorf("Expected %#v, got %#v\n", compare, me)
+	}
+}
+
+func TestBoard_Members(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	board := Board{ID: "1234", client: client}
+	compare := Members{
+		{ID: "1", Username: "alice", FullName: "Alice A", client: client},
+		{ID: "2", Username: "bob", FullName: "Bob B", client: client},
+	}
+
+	mux.HandleFunc("/boards/1234/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "1", "username": "alice", "fullName": "Alice A"}, {"id": "2", "username": "bob", "fullName": "Bob B"}]`)
+	})
+
+	members, err := board.Members()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, members) {
+		t.Errorf("Expected %#v, got %#v\n", compare, members)
+	}
+}
+
+func TestCard_Members(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "1234", IDMembers: []string{"1"}, client: client}
+	compare := Members{{ID: "1", Username: "alice", FullName: "Alice A", client: client}}
+
+	mux.HandleFunc("/cards/1234/members", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "1", "username": "alice", "fullName": "Alice A"}]`)
+	})
+
+	members, err := card.Members()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, members) {
+		t.Errorf("Expected %#v, got %#v\n", compare, members)
+	}
+}
+
+func TestCard_AddMember(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	cases := []struct {
+		MemberID string
+		Card     Card
+		EndCard  Card
+		Requests int
+	}{
+		{MemberID: "1",
+			Card:     Card{ID: "c", client: client},
+			EndCard:  Card{ID: "c", IDMembers: []string{"1"}, client: client},
+			Requests: 1},
+		{MemberID: "2",
+			Card:     Card{ID: "c", IDMembers: []string{"1"}, client: client},
+			EndCard:  Card{ID: "c", IDMembers: []string{"1", "2"}, client: client},
+			Requests: 1},
+		{MemberID: "1",
+			Card:     Card{ID: "c", IDMembers: []string{"1"}, client: client},
+			EndCard:  Card{ID: "c", IDMembers: []string{"1"}, client: client},
+			Requests: 0},
+	}
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c/idMembers", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "[]")
+	})
+
+	for _, c := range cases {
+		requests = 0
+
+		if err := c.Card.AddMember(c.MemberID); err != nil {
+			t.Fatal(err)
+		}
+
+		if c.Requests != requests {
+			t.Errorf("Expected %d requests, got %d\n", c.Requests, requests)
+		}
+		if c.Requests > 0 && body["value"] != c.MemberID {
+			t.Errorf("Expected %q, got %q\n", c.MemberID, body["value"])
+		}
+		if !reflect.DeepEqual(c.EndCard, c.Card) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndCard, c.Card)
+		}
+	}
+}
+
+func TestCard_RemoveMember(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "[]")
+	})
+
+	card := Card{ID: "c", IDMembers: []string{"1", "2"}, client: client}
+	if err := card.RemoveMember("2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "DELETE /cards/c/idMembers/2"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	compare := Card{ID: "c", IDMembers: []string{"1"}, client: client}
+	if !reflect.DeepEqual(compare, card) {
+		t.Errorf("Expected %#v, got %#v\n", compare, card)
+	}
+}
+
+func TestMembers_Find(t *testing.T) {
+	member1 := Member{ID: "2345", Username: "alice"}
+	member2 := Member{ID: "3456", Username: "bob"}
+	cases := []struct {
+		Username    string
+		FoundMember *Member
+		Members     Members
+		Err         error
+	}{
+		{Username: member1.Username,
+			FoundMember: &member1,
+			Members:     Members{member1, member2},
+			Err:         nil},
+		{Username: member2.Username,
+			FoundMember: &member2,
+			Members:     Members{member1, member2},
+			Err:         nil},
+		{Username: "carol",
+			FoundMember: &Member{},
+			Members:     Members{member1, member2},
+			Err:         NotFoundError{Type: "Member", Identifier: "carol"}},
+	}
+
+	for _, c := range cases {
+		member, err := c.Members.Find(c.Username)
+		if c.Err != err {
+			t.Errorf("Expected %q, got %q\n", c.Err, err)
+		}
+
+		if !reflect.DeepEqual(c.FoundMember, member) {
+			t.Errorf("Expected %#v, got %#v\n", c.FoundMember, member)
+		}
+	}
+}
+
+func TestCard_Comment(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "c", Name: "Card", client: client}
+	text := "Build **passed** & deployed + done\n\n```\n" + strings.Repeat("log line #1 100% ok?\n", 500) + "```"
+
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c/actions/comments", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		data, _ := json.Marshal(map[string]interface{}{"text": body["text"], "card": map[string]string{"id": "c"}})
+		fmt.Fprintf(w, `{"id": "a1", "type": "commentCard", "date": "2020-01-02T03:04:05.000Z", "idMemberCreator": "m1",
+			"memberCreator": {"id": "m1", "username": "ci"}, "data": %s}`, data)
+	})
+
+	comment, err := card.Comment(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body["text"] != text {
+		t.Errorf("Expected the text to be sent unchanged, got %q\n", body["text"])
+	}
+	if comment.Text != text || comment.ID != "a1" || !reflect.DeepEqual(card, comment.Card) {
+		t.Errorf("Expected comment a1 on card c with the text, got %#v\n", comment)
+	}
+	compareAuthor := Member{ID: "m1", Username: "ci", client: client}
+	if !reflect.DeepEqual(compareAuthor, comment.MemberCreator) {
+		t.Errorf("Expected %#v, got %#v\n", compareAuthor, comment.MemberCreator)
+	}
+	if want := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC); !want.Equal(comment.Date) {
+		t.Errorf("Expected %s, got %s\n", want, comment.Date)
+	}
+
+	if _, err := card.Comment(strings.Repeat("x", 16385)); err != ErrCommentTooLong {
+		t.Errorf("Expected %q, got %q\n", ErrCommentTooLong, err)
+	}
+}
+
+func TestCard_Comments(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "c", client: client}
+
+	var query url.Values
+	mux.HandleFunc("/cards/c/actions", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `[
+			{"id": "a2", "type": "commentCard", "idMemberCreator": "m2", "memberCreator": {"id": "m2", "username": "bob"}, "data": {"text": "Second"}},
+			{"id": "a1", "type": "commentCard", "idMemberCreator": "m1", "memberCreator": {"id": "m1", "username": "alice"}, "data": {"text": "First"}}]`)
+	})
+
+	comments, err := card.Comments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("filter") != "commentCard" {
+		t.Errorf("Expected the commentCard filter, got %q\n", query.Get("filter"))
+	}
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d\n", len(comments))
+	}
+	cases := []struct {
+		ID       string
+		Text     string
+		Username string
+	}{
+		{ID: "a2", Text: "Second", Username: "bob"},
+		{ID: "a1", Text: "First", Username: "alice"},
+	}
+	for i, c := range cases {
+		comment := comments[i]
+		if comment.ID != c.ID || comment.Text != c.Text || comment.MemberCreator.Username != c.Username {
+			t.Errorf("Expected %s %q by %s, got %s %q by %s\n", c.ID, c.Text, c.Username,
+				comment.ID, comment.Text, comment.MemberCreator.Username)
+		}
+		if !reflect.DeepEqual(card, comment.Card) {
+			t.Errorf("Expected %#v, got %#v\n", card, comment.Card)
+		}
+	}
+}
+
+func TestCard_CommentsPaged(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	defer func(size int) { commentsPageSize = size }(commentsPageSize)
+	commentsPageSize = 2
+
+	card := Card{ID: "c", client: client}
+
+	pages := map[string]string{
+		"":   `[{"id": "a5", "type": "commentCard", "data": {"text": "5"}}, {"id": "a4", "type": "commentCard", "data": {"text": "4"}}]`,
+		"a4": `[{"id": "a3", "type": "commentCard", "data": {"text": "3"}}, {"id": "a2", "type": "commentCard", "data": {"text": "2"}}]`,
+		"a2": `[{"id": "a1", "type": "commentCard", "data": {"text": "1"}}]`,
+	}
+	var befores []string
+	mux.HandleFunc("/cards/c/actions", func(w http.ResponseWriter, r *http.Request) {
+		if limit := r.URL.Query().Get("limit"); limit != "2" {
+			t.Errorf("Expected a limit of 2, got %q\n", limit)
+		}
+		before := r.URL.Query().Get("before")
+		befores = append(befores, before)
+		fmt.Fprint(w, pages[before])
+	})
+
+	comments, err := card.Comments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	text := ""
+	for _, comment := range comments {
+		text += comment.Text
+	}
+	if text != "54321" {
+		t.Errorf("Expected %q, got %q\n", "54321", text)
+	}
+	if compare := []string{"", "a4", "a2"}; !reflect.DeepEqual(compare, befores) {
+		t.Errorf("Expected %q, got %q\n", compare, befores)
+	}
+}
+
+func TestCard_EditComment(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "c", client: client}
+
+	path := ""
+	var body map[string]interface{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	if err := card.EditComment("a1", "Fixed *typo*"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "PUT /cards/c/actions/a1/comments"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if body["text"] != "Fixed *typo*" {
+		t.Errorf("Expected %q, got %q\n", "Fixed *typo*", body["text"])
+	}
+
+	if err := card.DeleteComment("a1"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "DELETE /cards/c/actions/a1/comments"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+}
+
+func TestCard_Attachments(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "c", Name: "Card", client: client}
+
+	mux.HandleFunc("/cards/c/attachments", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "a1", "name": "report.pdf", "url": "https://trello.com/report.pdf", "mimeType": "application/pdf",
+			"bytes": 1024, "date": "2020-01-02T03:04:05.000Z", "isUpload": true, "idMember": "m1"}]`)
+	})
+
+	compare := Attachments{{ID: "a1", Name: "report.pdf", URL: "https://trello.com/report.pdf", MimeType: "application/pdf",
+		Bytes: 1024, Date: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC), IsUpload: true, IDMember: "m1", Card: card, client: client}}
+
+	attachments, err := card.Attachments()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(compare, attachments) {
+		t.Errorf("Expected %#v, got %#v\n", compare, attachments)
+	}
+}
+
+func TestCard_AttachURL(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	card := Card{ID: "c", client: client}
+
+	path := ""
+	var body map[string]interface{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "a1", "name": "Design", "url": "https://example.com/design", "isUpload": false}`)
+	})
+
+	attachment, err := card.AttachURL("https://example.com/design", "Design")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "POST /cards/c/attachments"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	compareBody := map[string]interface{}{"url": "https://example.com/design", "name": "Design"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	compare := Attachment{ID: "a1", Name: "Design", URL: "https://example.com/design", Card: card, client: client}
+	if !reflect.DeepEqual(compare, attachment) {
+		t.Errorf("Expected %#v, got %#v\n", compare, attachment)
+	}
+}
+
+func TestCard_AttachFile(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 2, RetryNonIdempotent: true}
+
+	card := Card{ID: "c", client: client}
+	content := strings.Repeat("line of the build log\n", 10000)
+	dir, err := ioutil.TempDir("", "trel")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "build.log")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := 0
+	name, filename, got := "", "", ""
+	var contentLength int64
+	mux.HandleFunc("/cards/c/attachments", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		contentLength = r.ContentLength
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+		}
+		name = r.FormValue("name")
+		if file, header, err := r.FormFile("file"); err == nil {
+			b, _ := ioutil.ReadAll(file)
+			filename, got = header.Filename, string(b)
+		}
+		// The first attempt fails, so the file has to be sent again.
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id": "a1", "name": "build.log", "isUpload": true}`)
+	})
+
+	attachment, err := card.AttachFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d\n", attempts)
+	}
+	if name != "build.log" || filename != "build.log" {
+		t.Errorf("Expected the name and filename build.log, got %q and %q\n", name, filename)
+	}
+	if content != got {
+		t.Errorf("Expected the file content to be uploaded, got %d bytes\n", len(got))
+	}
+	// The file is streamed, so its length isn't known up front.
+	if contentLength != -1 {
+		t.Errorf("Expected a chunked upload, got a Content-Length of %d\n", contentLength)
+	}
+	if attachment.ID != "a1" || attachment.Card.ID != "c" {
+		t.Errorf("Expected attachment a1 on card c, got %#v\n", attachment)
+	}
+
+	if _, err := card.AttachFile(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("Expected a not exist error, got %v\n", err)
+	}
+}
+
+func TestAttachment_Delete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	attachment := Attachment{ID: "a1", Name: "build.log", Card: Card{ID: "c"}, client: client}
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "{}")
+	})
+
+	if err := attachment.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "DELETE /cards/c/attachments/a1"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if !reflect.DeepEqual(Attachment{}, attachment) {
+		t.Errorf("Expected an empty attachment, got %#v\n", attachment)
+	}
+}
+
+func TestAttachment_Download(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.APIKey, client.Token = "key", "token"
+
+	content := strings.Repeat("x", 100000)
+	authorization := ""
+	mux.HandleFunc("/download/", func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, content)
+	})
+	mux.HandleFunc("/download/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	cases := []struct {
+		IsUpload      bool
+		Authorization string
+	}{
+		{IsUpload: true, Authorization: `OAuth oauth_consumer_key="key", oauth_token="token"`},
+		{IsUpload: false, Authorization: ""},
+	}
+
+	for _, c := range cases {
+		attachment := Attachment{ID: "a1", URL: server.URL + "/download/build.log", IsUpload: c.IsUpload, client: client}
+		var buf bytes.Buffer
+		n, err := attachment.Download(&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if n != int64(len(content)) || buf.String() != content {
+			t.Errorf("Expected %d bytes of content, got %d\n", len(content), n)
+		}
+		if c.Authorization != authorization {
+			t.Errorf("Expected %q, got %q\n", c.Authorization, authorization)
+		}
+	}
+
+	attachment := Attachment{URL: server.URL + "/download/missing?token=token", IsUpload: true, client: client}
+	_, err := attachment.Download(ioutil.Discard)
+	if !IsNotFound(err) {
+		t.Errorf("Expected a not found error, got %v\n", err)
+	}
+	if strings.Contains(err.Error(), "token=token") {
+		t.Errorf("Expected the token to be redacted, got %q\n", err)
+	}
+}
+
+func TestClient_CardDates(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/cards/1", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "1", "due": "2020-01-02T03:04:05.678Z", "start": null, "dueComplete": true}`)
+	})
+	mux.HandleFunc("/cards/2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "2", "due": null, "start": "2020-01-01T00:00:00.000Z", "dueComplete": false}`)
+	})
+
+	cases := []struct {
+		ID          string
+		Due         time.Time
+		Start       time.Time
+		DueComplete bool
+	}{
+		{ID: "1", Due: time.Date(2020, 1, 2, 3, 4, 5, 678e6, time.UTC), Start: time.Time{}, DueComplete: true},
+		{ID: "2", Due: time.Time{}, Start: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), DueComplete: false},
+	}
+
+	for _, c := range cases {
+		card, err := client.Card(c.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !c.Due.Equal(card.Due) || !c.Start.Equal(card.Start) || c.DueComplete != card.DueComplete {
+			t.Errorf("Expected due %s, start %s and complete %t, got %s, %s and %t\n",
+				c.Due, c.Start, c.DueComplete, card.Due, card.Start, card.DueComplete)
+		}
+	}
+}
+
+func TestCard_SetDue(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	card := Card{ID: "c", client: client}
+	due := time.Date(2020, 1, 2, 4, 4, 5, 0, time.FixedZone("CET", 3600))
+
+	if err := card.SetDue(due); err != nil {
+		t.Fatal(err)
+	}
+	compareBody := map[string]interface{}{"due": "2020-01-02T03:04:05.000Z"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if !card.Due.Equal(due) {
+		t.Errorf("Expected %s, got %s\n", due, card.Due)
+	}
+
+	if err := card.ClearDue(); err != nil {
+		t.Fatal(err)
+	}
+	compareBody = map[string]interface{}{"due": nil}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if !card.Due.IsZero() {
+		t.Errorf("Expected no due date, got %s\n", card.Due)
+	}
+
+	start := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	if err := card.SetStart(start); err != nil {
+		t.Fatal(err)
+	}
+	compareBody = map[string]interface{}{"start": "2020-01-01T09:00:00.000Z"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if !card.Start.Equal(start) {
+		t.Errorf("Expected %s, got %s\n", start, card.Start)
+	}
+}
+
+func TestCard_MarkDueComplete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	card := Card{ID: "c", client: client}
+	if err := card.MarkDueComplete(true); err != nil {
+		t.Fatal(err)
+	}
+	if body["dueComplete"] != true || !card.DueComplete {
+		t.Errorf("Expected the card to be marked complete, got %#v and %t\n", body, card.DueComplete)
+	}
+
+	// Already complete, so nothing is sent.
+	if err := card.MarkDueComplete(true); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d\n", requests)
+	}
+}
+
+func TestCard_Archive(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	card := Card{ID: "c", client: client}
+	if err := card.Archive(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != true || !card.Closed {
+		t.Errorf("Expected the card to be archived, got %#v and %t\n", body, card.Closed)
+	}
+
+	// Already archived, so nothing is sent.
+	if err := card.Archive(); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d\n", requests)
+	}
+
+	if err := card.Unarchive(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != false || card.Closed {
+		t.Errorf("Expected the card to be unarchived, got %#v and %t\n", body, card.Closed)
+	}
+}
+
+func TestCard_Delete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "{}")
+	})
+
+	card := Card{ID: "c", Name: "Card", client: client}
+	if err := card.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "DELETE /cards/c"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if !reflect.DeepEqual(Card{}, card) {
+		t.Errorf("Expected an empty card, got %#v\n", card)
+	}
+}
+
+func TestCard_Copy(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	board := Board{ID: "b2", client: client}
+	list := List{ID: "l2", Board: board, client: client}
+	card := Card{ID: "c", Name: "Card", Description: "Details", IDList: "l1", client: client}
+
+	cases := []struct {
+		Options CopyOptions
+		Body    map[string]interface{}
+	}{
+		// Nothing is kept, so it's a new card with the name and description.
+		{Options: CopyOptions{},
+			Body: map[string]interface{}{"idList": "l2", "name": "Card", "desc": "Details"}},
+		{Options: CopyOptions{Name: "Copy", Position: "bottom"},
+			Body: map[string]interface{}{"idList": "l2", "name": "Copy", "desc": "Details", "pos": "bottom"}},
+		{Options: CopyOptions{Due: true, Start: true},
+			Body: map[string]interface{}{"idList": "l2", "idCardSource": "c", "keepFromSource": "due,start"}},
+		{Options: CopyOptions{Name: "Copy", Position: "top", Checklists: true, Labels: true, Members: true},
+			Body: map[string]interface{}{"idList": "l2", "idCardSource": "c", "keepFromSource": "checklists,labels,members",
+				"name": "Copy", "pos": "top"}},
+		{Options: CopyOptions{Attachments: true, Comments: true},
+			Body: map[string]interface{}{"idList": "l2", "idCardSource": "c", "keepFromSource": "attachments,comments"}},
+	}
+
+	var body map[string]interface{}
+	mux.HandleFunc("/cards", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "c2", "name": "Card", "idList": "l2", "idBoard": "b2"}`)
+	})
+
+	for _, c := range cases {
+		copied, err := card.Copy(list, c.Options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.Body, body) {
+			t.Errorf("Expected %#v, got %#v\n", c.Body, body)
+		}
+		compare := Card{ID: "c2", Name: "Card", IDList: "l2", IDBoard: "b2", List: list, Board: board, client: client}
+		if !reflect.DeepEqual(compare, copied) {
+			t.Errorf("Expected %#v, got %#v\n", compare, copied)
+		}
+	}
+	if card.ID != "c" || card.IDList != "l1" {
+		t.Errorf("Expected the original card to be unchanged, got %#v\n", card)
+	}
+}
+
+func TestCard_MoveTo(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	board1 := Board{ID: "b1", Name: "Board 1", client: client}
+	board2 := Board{ID: "b2", Name: "Board 2", client: client}
+	list1 := List{ID: "l1", IDBoard: "b1", Board: board1, client: client}
+	list2 := List{ID: "l2", IDBoard: "b2", Board: board2, client: client}
+
+	cases := []struct {
+		List     List
+		Position string
+		Body     map[string]interface{}
+		Response string
+		EndCard  Card
+	}{
+		{List: list2, Position: "top",
+			Body:     map[string]interface{}{"idList": "l2", "idBoard": "b2", "pos": "top"},
+			Response: `{"id": "c", "idList": "l2", "idBoard": "b2", "pos": 1024, "idLabels": ["lb2"], "labels": [{"id": "lb2", "idBoard": "b2"}]}`,
+			EndCard: Card{ID: "c", IDList: "l2", IDBoard: "b2", Pos: 1024, List: list2, Board: board2,
+				IDLabels: []string{"lb2"}, Labels: Labels{{ID: "lb2", IDBoard: "b2", client: client}}, client: client}},
+		{List: list2, Position: "2048.5",
+			Body:     map[string]interface{}{"idList": "l2", "idBoard": "b2", "pos": "2048.5"},
+			Response: `{"id": "c", "idList": "l2", "idBoard": "b2", "pos": 2048.5}`,
+			EndCard: Card{ID: "c", IDList: "l2", IDBoard: "b2", Pos: 2048.5, List: list2, Board: board2,
+				IDLabels: []string{"lb2"}, Labels: Labels{{ID: "lb2", IDBoard: "b2", client: client}}, client: client}},
+		// The board isn't known, so it's taken from the response.
+		{List: List{ID: "l3"}, Position: "",
+			Body:     map[string]interface{}{"idList": "l3"},
+			Response: `{"id": "c", "idList": "l3", "idBoard": "b3", "pos": 1}`,
+			EndCard: Card{ID: "c", IDList: "l3", IDBoard: "b3", Pos: 1,
+				List:  List{ID: "l3", IDBoard: "b3", Board: Board{ID: "b3", client: client}, client: client},
+				Board: Board{ID: "b3", client: client}, client: client}},
+	}
+
+	response := ""
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, response)
+	})
+
+	card := Card{ID: "c", IDList: "l1", IDBoard: "b1", List: list1, Board: board1,
+		IDLabels: []string{"lb1"}, Labels: Labels{{ID: "lb1", IDBoard: "b1", client: client}}, client: client}
+	for _, c := range cases {
+		response = c.Response
+		if err := card.MoveTo(c.List, c.Position); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.Body, body) {
+			t.Errorf("Expected %#v, got %#v\n", c.Body, body)
+		}
+		if !reflect.DeepEqual(c.EndCard, card) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndCard, card)
+		}
+	}
+}
+
+func TestCard_MoveAfter(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	mux.HandleFunc("/lists/l2/cards", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"id": "x", "pos": 100}, {"id": "z", "pos": 300}, {"id": "c", "pos": 250}, {"id": "y", "pos": 200}]`)
+	})
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "c", "idList": "l2", "idBoard": "b2"}`)
+	})
+
+	cases := []struct {
+		After    string
+		Position interface{}
+		Err      error
+	}{
+		{After: "x", Position: "150", Err: nil},
+		// The card itself is skipped, so it goes between y and z.
+		{After: "y", Position: "250", Err: nil},
+		{After: "z", Position: "bottom", Err: nil},
+		{After: "missing", Position: nil, Err: NotFoundError{Type: "Card", Identifier: "missing"}},
+	}
+
+	for _, c := range cases {
+		body = nil
+		card := Card{ID: "c", IDList: "l1", IDBoard: "b1", client: client}
+		other := Card{ID: c.After, IDList: "l2", IDBoard: "b2"}
+
+		if err := card.MoveAfter(other); c.Err != err {
+			t.Errorf("Expected %v, got %v\n", c.Err, err)
+		}
+		if c.Err != nil {
+			continue
+		}
+		if body["pos"] != c.Position || body["idBoard"] != "b2" {
+			t.Errorf("Expected position %v on board b2, got %#v\n", c.Position, body)
+		}
+		if card.IDList != "l2" || card.List.ID != "l2" || card.IDBoard != "b2" || card.Board.ID != "b2" {
+			t.Errorf("Expected the card on list l2 and board b2, got %#v\n", card)
+		}
+	}
+}
+
+func TestList_Rename(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/lists/l", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	list := List{ID: "l", Name: "To Do", client: client}
+	if err := list.Rename("Doing"); err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "Doing" || list.Name != "Doing" {
+		t.Errorf("Expected the list to be renamed, got %#v and %q\n", body, list.Name)
+	}
+
+	// Same name, so nothing is sent.
+	if err := list.Rename("Doing"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d\n", requests)
+	}
+}
+
+func TestList_Archive(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/lists/l", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	list := List{ID: "l", client: client}
+	if err := list.Archive(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != true || !list.Closed {
+		t.Errorf("Expected the list to be archived, got %#v and %t\n", body, list.Closed)
+	}
+
+	if err := list.Unarchive(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != false || list.Closed {
+		t.Errorf("Expected the list to be unarchived, got %#v and %t\n", body, list.Closed)
+	}
+}
+
+func TestList_SetPosition(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/lists/l", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "l", "pos": 8192}`)
+	})
+
+	list := List{ID: "l", Pos: 65536, client: client}
+	if err := list.SetPosition("top"); err != nil {
+		t.Fatal(err)
+	}
+	if body["pos"] != "top" || list.Pos != 8192 {
+		t.Errorf("Expected the list at the top, got %#v and %v\n", body, list.Pos)
+	}
+}
+
+func TestList_MoveToBoard(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/lists/l", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "l", "idBoard": "b2", "pos": 131072}`)
+	})
+
+	board := Board{ID: "b2", Name: "Archive", client: client}
+	list := List{ID: "l", IDBoard: "b1", Board: Board{ID: "b1"}, client: client}
+	if err := list.MoveToBoard(board, "bottom"); err != nil {
+		t.Fatal(err)
+	}
+
+	compareBody := map[string]interface{}{"idBoard": "b2", "pos": "bottom"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	compare := List{ID: "l", IDBoard: "b2", Pos: 131072, Board: board, client: client}
+	if !reflect.DeepEqual(compare, list) {
+		t.Errorf("Expected %#v, got %#v\n", compare, list)
+	}
+}
+
+func TestList_MoveAllCards(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/lists/done/moveAllCards", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected %s, got %s\n", http.MethodPost, r.Method)
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `[{"id": "c1", "idList": "archive", "idBoard": "b2"}, {"id": "c2", "idList": "archive", "idBoard": "b2"}]`)
+	})
+
+	board1 := Board{ID: "b1", client: client}
+	board2 := Board{ID: "b2", client: client}
+	done := List{ID: "done", IDBoard: "b1", Board: board1, client: client}
+	archive := List{ID: "archive", IDBoard: "b2", Board: board2, client: client}
+
+	from := Cards{{ID: "c1", IDList: "done"}, {ID: "c2", IDList: "done"}}
+	into := Cards{{ID: "c0", IDList: "archive"}}
+	moved, err := done.MoveAllCards(archive, &from, &into)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareBody := map[string]interface{}{"idBoard": "b2", "idList": "archive"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	compare := Cards{
+		{ID: "c1", IDList: "archive", IDBoard: "b2", List: archive, Board: board2, client: client},
+		{ID: "c2", IDList: "archive", IDBoard: "b2", List: archive, Board: board2, client: client},
+	}
+	if !reflect.DeepEqual(compare, moved) {
+		t.Errorf("Expected %#v, got %#v\n", compare, moved)
+	}
+	if len(from) != 0 {
+		t.Errorf("Expected no cards left, got %#v\n", from)
+	}
+	if want := append(Cards{{ID: "c0", IDList: "archive"}}, compare...); !reflect.DeepEqual(want, into) {
+		t.Errorf("Expected %#v, got %#v\n", want, into)
+	}
+
+	// Without a board on to, the cards stay on the list's board.
+	if _, err := done.MoveAllCards(List{ID: "archive"}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if body["idBoard"] != "b1" {
+		t.Errorf("Expected %q, got %q\n", "b1", body["idBoard"])
+	}
+}
+
+func TestList_ArchiveAllCards(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	archived := false
+	mux.HandleFunc("/lists/done/cards", func(w http.ResponseWriter, r *http.Request) {
+		if archived {
+			t.Error("Expected the cards to be listed before archiving them")
+		}
+		fmt.Fprint(w, `[{"id": "c1"}, {"id": "c2"}]`)
+	})
+	mux.HandleFunc("/lists/done/archiveAllCards", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected %s, got %s\n", http.MethodPost, r.Method)
+		}
+		archived = true
+		fmt.Fprint(w, "{}")
+	})
+
+	done := List{ID: "done", client: client}
+	cards := Cards{{ID: "c1"}, {ID: "c2"}, {ID: "other"}}
+	out, err := done.ArchiveAllCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !archived {
+		t.Error("Expected the cards to be archived")
+	}
+	if len(out) != 2 || !out[0].Closed || !out[1].Closed {
+		t.Errorf("Expected 2 archived cards, got %#v\n", out)
+	}
+	if !cards[0].Closed || !cards[1].Closed || cards[2].Closed {
+		t.Errorf("Expected only c1 and c2 to be closed, got %#v\n", cards)
+	}
+}
+
+func TestClient_NewBoard(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	cases := []struct {
+		Options BoardOptions
+		Body    map[string]interface{}
+	}{
+		{Options: BoardOptions{},
+			Body: map[string]interface{}{"name": "Project", "defaultLists": false}},
+		{Options: BoardOptions{DefaultLists: true, IDOrganization: "o1", PermissionLevel: "org"},
+			Body: map[string]interface{}{"name": "Project", "defaultLists": true, "idOrganization": "o1",
+				"prefs_permissionLevel": "org"}},
+		{Options: BoardOptions{IDBoardSource: "template"},
+			Body: map[string]interface{}{"name": "Project", "defaultLists": false, "idBoardSource": "template",
+				"keepFromSource": "none"}},
+		{Options: BoardOptions{IDBoardSource: "template", KeepCards: true},
+			Body: map[string]interface{}{"name": "Project", "defaultLists": false, "idBoardSource": "template",
+				"keepFromSource": "cards"}},
+	}
+
+	var body map[string]interface{}
+	mux.HandleFunc("/boards", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected %s, got %s\n", http.MethodPost, r.Method)
+		}
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "b1", "name": "Project", "closed": false, "idOrganization": "o1"}`)
+	})
+
+	compare := Board{ID: "b1", Name: "Project", IDOrganization: "o1", client: client}
+	for _, c := range cases {
+		board, err := client.NewBoard("Project", c.Options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(c.Body, body) {
+			t.Errorf("Expected %#v, got %#v\n", c.Body, body)
+		}
+		if !reflect.DeepEqual(compare, board) {
+			t.Errorf("Expected %#v, got %#v\n", compare, board)
+		}
+	}
+}
+
+func TestBoard_Rename(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	requests := 0
+	var body map[string]interface{}
+	mux.HandleFunc("/boards/b", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	board := Board{ID: "b", Name: "Project", client: client}
+	if err := board.Rename("Project 2"); err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "Project 2" || board.Name != "Project 2" {
+		t.Errorf("Expected the board to be renamed, got %#v and %q\n", body, board.Name)
+	}
+
+	// Same name, so nothing is sent.
+	if err := board.Rename("Project 2"); err != nil {
+		t.Fatal(err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected 1 request, got %d\n", requests)
+	}
+}
+
+func TestBoard_Close(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/boards/b", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	board := Board{ID: "b", client: client}
+	if err := board.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != true || !board.Closed {
+		t.Errorf("Expected the board to be closed, got %#v and %t\n", body, board.Closed)
+	}
+
+	if err := board.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if body["closed"] != false || board.Closed {
+		t.Errorf("Expected the board to be reopened, got %#v and %t\n", body, board.Closed)
+	}
+}
+
+func TestBoard_Delete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "{}")
+	})
+
+	board := Board{ID: "b", Name: "Project", client: client}
+	if err := board.Delete(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "DELETE /boards/b"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if !reflect.DeepEqual(Board{}, board) {
+		t.Errorf("Expected an empty board, got %#v\n", board)
+	}
+}
+
+func TestCard_NewChecklist(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/checklists", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected %s, got %s\n", http.MethodPost, r.Method)
+		}
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "cl2", "name": "Release", "idCard": "c", "checkItems": [{"id": "ci1", "name": "Tag", "state": "incomplete"}]}`)
+	})
+
+	card := Card{ID: "c", Board: Board{ID: "b"}, IDChecklists: []string{"cl1"}, client: client}
+	checklist, err := card.NewChecklist("Release", "template")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareBody := map[string]interface{}{"idCard": "c", "name": "Release", "idChecklistSource": "template"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if want := []string{"cl1", "cl2"}; !reflect.DeepEqual(want, card.IDChecklists) {
+		t.Errorf("Expected %v, got %v\n", want, card.IDChecklists)
+	}
+	if checklist.ID != "cl2" || checklist.Card.ID != "c" || checklist.Board.ID != "b" {
+		t.Errorf("Expected checklist cl2 on card c and board b, got %#v\n", checklist)
+	}
+	if len(checklist.CheckItems) != 1 || checklist.CheckItems[0].Checklist.ID != "cl2" || checklist.CheckItems[0].client != client {
+		t.Errorf("Expected the items to be wired to the checklist, got %#v\n", checklist.CheckItems)
+	}
+
+	if _, err := card.NewChecklist("Empty", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := body["idChecklistSource"]; ok {
+		t.Errorf("Expected no source checklist, got %#v\n", body)
+	}
+}
+
+func TestChecklist_Rename(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	var body map[string]interface{}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, "{}")
+	})
+
+	checklist := Checklist{ID: "cl", Name: "Release", client: client}
+	if err := checklist.Rename("Deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "PUT /checklists/cl"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if body["name"] != "Deploy" || checklist.Name != "Deploy" {
+		t.Errorf("Expected the checklist to be renamed, got %#v and %q\n", body, checklist.Name)
+	}
+
+	if err := checklist.Delete(); err != nil {
+		t.Fatal(err)
+	}
+	if want := "DELETE /checklists/cl"; want != path {
+		t.Errorf("Expected %q, got %q\n", want, path)
+	}
+	if !reflect.DeepEqual(Checklist{}, checklist) {
+		t.Errorf("Expected an empty checklist, got %#v\n", checklist)
+	}
+}
+
+func TestChecklist_AddItem(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/checklists/cl/checkItems", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "ci2", "name": "Tag", "state": "complete", "idChecklist": "cl", "pos": 100}`)
+	})
+
+	checklist := Checklist{ID: "cl", IDCard: "c", CheckItems: CheckItems{{ID: "ci1"}}, client: client}
+	item, err := checklist.AddItem("Tag", "top", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compareBody := map[string]interface{}{"name": "Tag", "pos": "top", "checked": true}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if item.ID != "ci2" || item.State != StateComplete || item.Pos != 100 || item.Checklist.IDCard != "c" || item.client != client {
+		t.Errorf("Expected complete item ci2 on the checklist, got %#v\n", item)
+	}
+	if len(checklist.CheckItems) != 2 || checklist.CheckItems[1].ID != "ci2" {
+		t.Errorf("Expected the item to be added to the checklist, got %#v\n", checklist.CheckItems)
+	}
+}
+
+func TestCheckItem_Delete(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	path := ""
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.Path
+		fmt.Fprint(w, "{}")
+	})
+
+	cases := []CheckItem{
+		{ID: "ci", IDChecklist: "cl", client: client},
+		{ID: "ci", Checklist: Checklist{ID: "cl"}, client: client},
+	}
+
+	for _, item := range cases {
+		if err := item.Delete(); err != nil {
+			t.Fatal(err)
+		}
+		if want := "DELETE /checklists/cl/checkItems/ci"; want != path {
+			t.Errorf("Expected %q, got %q\n", want, path)
+		}
+		if !reflect.DeepEqual(CheckItem{}, item) {
+			t.Errorf("Expected an empty item, got %#v\n", item)
+		}
+	}
+}
+
+func TestCheckItem_SetPosition(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+
+	var body map[string]interface{}
+	mux.HandleFunc("/cards/c/checkItem/ci", func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprint(w, `{"id": "ci", "idChecklist": "cl2", "pos": 50}`)
+	})
+
+	item := CheckItem{ID: "ci", IDChecklist: "cl1", Pos: 200, Checklist: Checklist{ID: "cl1", IDCard: "c"}, client: client}
+	if err := item.SetPosition("top"); err != nil {
+		t.Fatal(err)
+	}
+	if body["pos"] != "top" || item.Pos != 50 {
+		t.Errorf("Expected the item at the top, got %#v and %v\n", body, item.Pos)
+	}
+
+	other := Checklist{ID: "cl2", IDCard: "c"}
+	if err := item.MoveToChecklist(other, "bottom"); err != nil {
+		t.Fatal(err)
+	}
+	compareBody := map[string]interface{}{"idChecklist": "cl2", "pos": "bottom"}
+	if !reflect.DeepEqual(compareBody, body) {
+		t.Errorf("Expected %#v, got %#v\n", compareBody, body)
+	}
+	if item.IDChecklist != "cl2" || !reflect.DeepEqual(other, item.Checklist) {
+		t.Errorf("Expected the item on checklist cl2, got %#v\n", item)
+	}
+}
+
+func TestCheckItemState_JSON(t *testing.T) {
+	cases := []struct {
+		JSON  string
+		State CheckItemState
+		Err   bool
+	}{
+		{JSON: `"complete"`, State: StateComplete, Err: false},
+		{JSON: `"incomplete"`, State: StateIncomplete, Err: false},
+		{JSON: `"done"`, State: StateIncomplete, Err: true},
+		{JSON: `true`, State: StateIncomplete, Err: true},
+	}
+
+	for _, c := range cases {
+		var state CheckItemState
+		err := json.Unmarshal([]byte(c.JSON), &state)
+		if c.Err != (err != nil) {
+			t.Errorf("Expected error %t for %s, got %v\n", c.Err, c.JSON, err)
+		}
+		if c.Err {
+			continue
+		}
+		if c.State != state {
+			t.Errorf("Expected %v, got %v\n", c.State, state)
+		}
+
+		b, err := json.Marshal(state)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.JSON != string(b) {
+			t.Errorf("Expected %s, got %s\n", c.JSON, b)
+		}
+	}
+
+	var item CheckItem
+	if err := json.Unmarshal([]byte(`{"id": "ci", "state": "complete"}`), &item); err != nil {
+		t.Fatal(err)
+	}
+	if !item.Done() {
+		t.Errorf("Expected the item to be done, got %#v\n", item)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	checklists := Checklists{
+		{CheckItems: CheckItems{{State: StateComplete}, {State: StateIncomplete}, {State: StateComplete}}},
+		{CheckItems: CheckItems{{State: StateIncomplete}}},
+		{},
+	}
+
+	cases := []struct {
+		Progress Progress
+		Expected Progress
+		Percent  float64
+	}{
+		{Progress: checklists[0].Progress(), Expected: Progress{Completed: 2, Total: 3}, Percent: 200.0 / 3},
+		{Progress: checklists[1].Progress(), Expected: Progress{Completed: 0, Total: 1}, Percent: 0},
+		{Progress: checklists[2].Progress(), Expected: Progress{Completed: 0, Total: 0}, Percent: 0},
+		{Progress: checklists.Progress(), Expected: Progress{Completed: 2, Total: 4}, Percent: 50},
+	}
+
+	for _, c := range cases {
+		if c.Expected != c.Progress {
+			t.Errorf("Expected %#v, got %#v\n", c.Expected, c.Progress)
+		}
+		if c.Percent != c.Progress.Percent() {
+			t.Errorf("Expected %v, got %v\n", c.Percent, c.Progress.Percent())
+		}
+	}
+
+	var card Card
+	if err := json.Unmarshal([]byte(`{"id": "c", "badges": {"checkItems": 8, "checkItemsChecked": 6, "comments": 2}}`), &card); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Progress{Completed: 6, Total: 8}); want != card.Progress() {
+		t.Errorf("Expected %#v, got %#v\n", want, card.Progress())
+	}
+	if card.Progress().Percent() != 75 {
+		t.Errorf("Expected %v, got %v\n", 75, card.Progress().Percent())
+	}
+}
+
+func TestAttachment_DownloadRetryAndLimit(t *testing.T) {
+	resetBuckets()
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Retry = RetryPolicy{MaxAttempts: 2}
+	client.Limiter = NewRateLimiter("download-key", "download-token", RateLimits{KeyRequests: 2, TokenRequests: 2, Window: time.Minute})
+
+	attempts := 0
+	mux.HandleFunc("/download/build.log", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "log")
+	})
+
+	attachment := Attachment{URL: server.URL + "/download/build.log", IsUpload: true, client: client}
+	var buf bytes.Buffer
+	if _, err := attachment.Download(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 || buf.String() != "log" {
+		t.Errorf("Expected the download to be retried, got %d attempts and %q\n", attempts, buf.String())
+	}
+
+	// Both requests of the window were used, so the limiter makes this wait.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := attachment.DownloadContext(ctx, ioutil.Discard); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %q, got %q\n", context.DeadlineExceeded, err)
+	}
+	if attempts != 2 {
+		t.Errorf("Expected no more attempts, got %d\n", attempts)
+	}
+}
+
+func TestCard_Created(t *testing.T) {
+	cases := []struct {
+		ID      string
+		Created time.Time
+	}{
+		{ID: "5e0d5a80aaaaaaaaaaaaaaaa", Created: time.Date(2020, 1, 2, 2, 50, 40, 0, time.UTC)},
+		{ID: "short", Created: time.Time{}},
+		{ID: "not hex!aaaaaaaaaaaaaaaa", Created: time.Time{}},
+	}
+
+	for _, c := range cases {
+		if created := (Card{ID: c.ID}).Created(); !c.Created.Equal(created) {
+			t.Errorf("Expected %s, got %s\n", c.Created, created)
+		}
+	}
+}
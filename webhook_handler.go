@@ -0,0 +1,137 @@
+package trel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Deliveries larger than this are rejected.
+const maxWebhookBodySize = 10 << 20
+
+// WebhookEvent is a single webhook delivery. Model is the model the webhook
+// watches, in its current state. Use Action.Typed to decode the action.
+type WebhookEvent struct {
+	Action  Action          `json:"action"`
+	Model   json.RawMessage `json:"model"`
+	Webhook Webhook         `json:"webhook"`
+}
+
+// WebhookFunc handles a webhook delivery. If it returns an error the delivery
+// is answered with a 500 status, so Trello will send it again later.
+type WebhookFunc func(ctx context.Context, event WebhookEvent) error
+
+// WebhookHandler is an http.Handler for webhook callbacks. It answers
+// Trello's HEAD request when a webhook is created, and checks the signature
+// of every delivery before passing it on to the registered WebhookFuncs.
+type WebhookHandler struct {
+	// Secret is the OAuth secret of the app the webhooks were created with.
+	Secret string
+	// CallbackURL is the exact callbackURL of the webhooks, which Trello
+	// includes in the signature.
+	CallbackURL string
+
+	handlers map[string][]WebhookFunc
+	all      []WebhookFunc
+}
+
+func NewWebhookHandler(secret, callbackURL string) *WebhookHandler {
+	return &WebhookHandler{
+		Secret:      secret,
+		CallbackURL: callbackURL,
+		handlers:    map[string][]WebhookFunc{},
+	}
+}
+
+// Handle registers fn for actions of actionType, such as "createCard".
+func (h *WebhookHandler) Handle(actionType string, fn WebhookFunc) {
+	if h.handlers == nil {
+		h.handlers = map[string][]WebhookFunc{}
+	}
+	h.handlers[actionType] = append(h.handlers[actionType], fn)
+}
+
+// HandleAll registers fn for every action.
+func (h *WebhookHandler) HandleAll(fn WebhookFunc) {
+	h.all = append(h.all, fn)
+}
+
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodHead:
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodPost:
+	default:
+		w.Header().Set("Allow", "HEAD, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxWebhookBodySize {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	if !VerifyWebhookSignature(h.Secret, h.CallbackURL, body, r.Header.Get("X-Trello-Webhook")) {
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.dispatch(r.Context(), event); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *WebhookHandler) dispatch(ctx context.Context, event WebhookEvent) error {
+	for _, fn := range h.handlers[event.Action.Type] {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	for _, fn := range h.all {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyWebhookSignature reports whether signature, the X-Trello-Webhook
+// header of a delivery, matches its body. Trello signs the body followed by
+// the callback URL with HMAC-SHA1, using the app secret as the key.
+func VerifyWebhookSignature(secret, callbackURL string, body []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	got, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(got, webhookSignature(secret, callbackURL, body))
+}
+
+func webhookSignature(secret, callbackURL string, body []byte) []byte {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(callbackURL))
+	return mac.Sum(nil)
+}
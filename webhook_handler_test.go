@@ -0,0 +1,130 @@
+package trel
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testSecret      = "secret"
+	testCallbackURL = "https://example.com/webhooks/trello"
+)
+
+func signedRequest(method, body, secret string) *http.Request {
+	r := httptest.NewRequest(method, "/webhooks/trello", strings.NewReader(body))
+	signature := webhookSignature(secret, testCallbackURL, []byte(body))
+	r.Header.Set("X-Trello-Webhook", base64.StdEncoding.EncodeToString(signature))
+	return r
+}
+
+func TestWebhookHandler(t *testing.T) {
+	body := `{"action": {"id": "1", "type": "createCard", "date": "2020-01-02T03:04:05.000Z", "idMemberCreator": "2", "data": {"card": {"id": "3"}}},
+		"model": {"id": "4"}, "webhook": {"id": "5", "idModel": "4", "callbackURL": "https://example.com/webhooks/trello", "active": true}}`
+
+	cases := []struct {
+		Request *http.Request
+		Status  int
+		Called  bool
+	}{
+		{Request: httptest.NewRequest(http.MethodHead, "/webhooks/trello", nil), Status: http.StatusOK, Called: false},
+		{Request: signedRequest(http.MethodPost, body, testSecret), Status: http.StatusOK, Called: true},
+		{Request: signedRequest(http.MethodPost, body, "wrong secret"), Status: http.StatusUnauthorized, Called: false},
+		{Request: httptest.NewRequest(http.MethodPost, "/webhooks/trello", strings.NewReader(body)), Status: http.StatusUnauthorized, Called: false},
+		{Request: signedRequest(http.MethodPost, "not json", testSecret), Status: http.StatusBadRequest, Called: false},
+		{Request: signedRequest(http.MethodGet, body, testSecret), Status: http.StatusMethodNotAllowed, Called: false},
+	}
+
+	compare := WebhookEvent{
+		Action: Action{ID: "1", Type: "createCard", Date: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			IDMemberCreator: "2", Data: []byte(`{"card": {"id": "3"}}`)},
+		Model:   []byte(`{"id": "4"}`),
+		Webhook: Webhook{ID: "5", IDModel: "4", CallbackURL: testCallbackURL, Active: true},
+	}
+
+	handler := NewWebhookHandler(testSecret, testCallbackURL)
+	var called []string
+	var event WebhookEvent
+	handler.Handle("createCard", func(ctx context.Context, e WebhookEvent) error {
+		called = append(called, "createCard")
+		event = e
+		return nil
+	})
+	handler.Handle("updateCard", func(ctx context.Context, e WebhookEvent) error {
+		called = append(called, "updateCard")
+		return nil
+	})
+	handler.HandleAll(func(ctx context.Context, e WebhookEvent) error {
+		called = append(called, "all")
+		return nil
+	})
+
+	for _, c := range cases {
+		called, event = nil, WebhookEvent{}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, c.Request)
+		if c.Status != w.Code {
+			t.Errorf("Expected %d, got %d\n", c.Status, w.Code)
+		}
+
+		if !c.Called {
+			if called != nil {
+				t.Errorf("Expected no handlers to be called, got %v\n", called)
+			}
+			continue
+		}
+		if want := []string{"createCard", "all"}; !reflect.DeepEqual(want, called) {
+			t.Errorf("Expected %v, got %v\n", want, called)
+		}
+		if !reflect.DeepEqual(compare, event) {
+			t.Errorf("Expected %#v, got %#v\n", compare, event)
+		}
+	}
+}
+
+func TestWebhookHandler_Error(t *testing.T) {
+	handler := NewWebhookHandler(testSecret, testCallbackURL)
+	handler.HandleAll(func(ctx context.Context, e WebhookEvent) error {
+		return errors.New("failed")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, signedRequest(http.MethodPost, `{"action": {"type": "createCard"}}`, testSecret))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected %d, got %d\n", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"action": {}}`)
+	// printf '%s%s' "$body" "$callbackURL" | openssl sha1 -hmac secret -binary | base64
+	signature := "6yGlgyde6rEe3FSDrLb4yPqyX7M="
+
+	cases := []struct {
+		Secret      string
+		CallbackURL string
+		Body        []byte
+		Signature   string
+		Valid       bool
+	}{
+		{Secret: testSecret, CallbackURL: testCallbackURL, Body: body, Signature: signature, Valid: true},
+		{Secret: "other", CallbackURL: testCallbackURL, Body: body, Signature: signature, Valid: false},
+		{Secret: testSecret, CallbackURL: "https://example.com/other", Body: body, Signature: signature, Valid: false},
+		{Secret: testSecret, CallbackURL: testCallbackURL, Body: []byte(`{}`), Signature: signature, Valid: false},
+		{Secret: testSecret, CallbackURL: testCallbackURL, Body: body, Signature: "not base64!", Valid: false},
+		{Secret: "", CallbackURL: testCallbackURL, Body: body, Signature: signature, Valid: false},
+	}
+
+	for _, c := range cases {
+		if valid := VerifyWebhookSignature(c.Secret, c.CallbackURL, c.Body, c.Signature); c.Valid != valid {
+			t.Errorf("Expected %t, got %t\n", c.Valid, valid)
+		}
+	}
+}
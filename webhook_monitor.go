@@ -0,0 +1,120 @@
+package trel
+
+import (
+	"context"
+	"time"
+)
+
+// ExpectedWebhook is a webhook that a WebhookMonitor expects to exist.
+type ExpectedWebhook struct {
+	IDModel     string
+	CallbackURL string
+	Description string
+}
+
+// WebhookReport is the result of one WebhookMonitor check. Inactive and
+// Missing are what was found before any repairs; Repaired has the webhooks
+// that were reactivated or recreated, and Errors the repairs that failed.
+// Err is set if the webhooks could not be listed at all.
+type WebhookReport struct {
+	Time     time.Time
+	Active   Webhooks
+	Inactive Webhooks
+	Missing  []ExpectedWebhook
+	Repaired Webhooks
+	Errors   []error
+	Err      error
+}
+
+// Healthy reports whether every expected webhook was found and active.
+func (r WebhookReport) Healthy() bool {
+	return r.Err == nil && len(r.Inactive) == 0 && len(r.Missing) == 0
+}
+
+// WebhookMonitor periodically checks that the expected webhooks exist and are
+// active. Trello deactivates webhooks whose callbacks keep failing, so with
+// Repair set the monitor reactivates them, and recreates missing ones.
+type WebhookMonitor struct {
+	Client   *Client
+	Expected []ExpectedWebhook
+	// Interval is the time between checks, 10 minutes if it isn't set.
+	Interval time.Duration
+	Repair   bool
+	// OnReport, if set, is called with the report of every check.
+	OnReport func(WebhookReport)
+}
+
+// Run checks the webhooks right away and then every Interval, until ctx is
+// done.
+func (m *WebhookMonitor) Run(ctx context.Context) error {
+	interval := m.Interval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		report := m.Check(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if m.OnReport != nil {
+			m.OnReport(report)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check checks the webhooks once, repairing them if m.Repair is set.
+func (m *WebhookMonitor) Check(ctx context.Context) WebhookReport {
+	report := WebhookReport{Time: time.Now()}
+	webhooks, err := m.Client.WebhooksContext(ctx)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+
+	for _, expected := range m.Expected {
+		i := findWebhook(webhooks, expected)
+		switch {
+		case i == -1:
+			report.Missing = append(report.Missing, expected)
+		case !webhooks[i].Active:
+			report.Inactive = append(report.Inactive, webhooks[i])
+		default:
+			report.Active = append(report.Active, webhooks[i])
+			continue
+		}
+		if !m.Repair {
+			continue
+		}
+
+		var repaired Webhook
+		if i == -1 {
+			repaired, err = m.Client.NewWebhookContext(ctx, expected.Description, expected.CallbackURL, expected.IDModel)
+		} else {
+			repaired = webhooks[i]
+			err = repaired.ActivateContext(ctx)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+			continue
+		}
+		report.Repaired = append(report.Repaired, repaired)
+	}
+	return report
+}
+
+func findWebhook(ws Webhooks, expected ExpectedWebhook) int {
+	for i := range ws {
+		if ws[i].IDModel == expected.IDModel && ws[i].CallbackURL == expected.CallbackURL {
+			return i
+		}
+	}
+	return -1
+}
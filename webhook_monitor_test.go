@@ -0,0 +1,121 @@
+package trel
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWebhookMonitor_Check(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Token = "token"
+
+	ws := &webhookServer{}
+	mux.Handle("/", ws)
+
+	active := Webhook{ID: "1", IDModel: "m1", CallbackURL: "https://example.com/1", Description: "One", Active: true}
+	inactive := Webhook{ID: "2", IDModel: "m2", CallbackURL: "https://example.com/2", Description: "Two", Active: false}
+	missing := ExpectedWebhook{IDModel: "m3", CallbackURL: "https://example.com/3", Description: "Three"}
+	expected := []ExpectedWebhook{
+		{IDModel: "m1", CallbackURL: "https://example.com/1", Description: "One"},
+		{IDModel: "m2", CallbackURL: "https://example.com/2", Description: "Two"},
+		missing,
+	}
+
+	cases := []struct {
+		Repair      bool
+		Report      WebhookReport
+		EndWebhooks Webhooks
+	}{
+		{Repair: false,
+			Report: WebhookReport{Active: Webhooks{active}, Inactive: Webhooks{inactive},
+				Missing: []ExpectedWebhook{missing}},
+			EndWebhooks: Webhooks{active, inactive}},
+		{Repair: true,
+			Report: WebhookReport{Active: Webhooks{active}, Inactive: Webhooks{inactive},
+				Missing: []ExpectedWebhook{missing},
+				Repaired: Webhooks{
+					{ID: "2", IDModel: "m2", CallbackURL: "https://example.com/2", Description: "Two", Active: true},
+					{ID: "new1", IDModel: "m3", CallbackURL: "https://example.com/3", Description: "Three", Active: true}}},
+			EndWebhooks: Webhooks{active,
+				{ID: "2", IDModel: "m2", CallbackURL: "https://example.com/2", Description: "Two", Active: true},
+				{ID: "new1", IDModel: "m3", CallbackURL: "https://example.com/3", Description: "Three", Active: true}}},
+	}
+
+	for _, c := range cases {
+		ws.webhooks = Webhooks{active, inactive}
+		ws.nextID = 0
+
+		monitor := WebhookMonitor{Client: client, Expected: expected, Repair: c.Repair}
+		report := monitor.Check(context.Background())
+		if report.Time.IsZero() {
+			t.Errorf("Expected the report time to be set\n")
+		}
+		if report.Healthy() {
+			t.Errorf("Expected the report not to be healthy\n")
+		}
+
+		for _, webhooks := range []Webhooks{c.Report.Active, c.Report.Inactive, c.Report.Repaired} {
+			for i := range webhooks {
+				webhooks[i].client = client
+			}
+		}
+		c.Report.Time = report.Time
+		if !reflect.DeepEqual(c.Report, report) {
+			t.Errorf("Expected %#v, got %#v\n", c.Report, report)
+		}
+		if got := ws.stored(); !reflect.DeepEqual(c.EndWebhooks, got) {
+			t.Errorf("Expected %#v, got %#v\n", c.EndWebhooks, got)
+		}
+	}
+}
+
+func TestWebhookMonitor_Run(t *testing.T) {
+	client, mux, server := setupClientMuxServer()
+	defer server.Close()
+	client.Token = "token"
+
+	ws := &webhookServer{webhooks: Webhooks{{ID: "1", IDModel: "m1", CallbackURL: "https://example.com/1", Active: true}}}
+	mux.Handle("/", ws)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var reports []WebhookReport
+	monitor := WebhookMonitor{
+		Client:   client,
+		Expected: []ExpectedWebhook{{IDModel: "m1", CallbackURL: "https://example.com/1"}},
+		Interval: time.Millisecond,
+		OnReport: func(r WebhookReport) {
+			reports = append(reports, r)
+			if len(reports) == 3 {
+				cancel()
+			}
+		},
+	}
+
+	if err := monitor.Run(ctx); err != context.Canceled {
+		t.Errorf("Expected %q, got %q\n", context.Canceled, err)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %d\n", len(reports))
+	}
+	for _, r := range reports {
+		if !r.Healthy() {
+			t.Errorf("Expected a healthy report, got %#v\n", r)
+		}
+	}
+}
+
+func TestWebhookMonitor_CheckError(t *testing.T) {
+	client, _, server := setupClientMuxServer()
+	server.Close()
+
+	monitor := WebhookMonitor{Client: client, Expected: []ExpectedWebhook{{IDModel: "m1"}}}
+	report := monitor.Check(context.Background())
+	if report.Err == nil || report.Healthy() {
+		t.Errorf("Expected an unhealthy report with an error, got %#v\n", report)
+	}
+}